@@ -0,0 +1,229 @@
+// Package accounts 管理多个Odin.fun账户，并发地对所有账户执行操作
+//
+// 每个账户拥有独立的身份、odin_api客户端和会话，以及按需创建的agent_sdk客户端。
+// 认证在账户第一次发送需要授权的请求时才会进行。
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/MrHat365/odin-go/agent_sdk"
+	"github.com/MrHat365/odin-go/keystore"
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/aviate-labs/agent-go"
+)
+
+// DefaultConcurrency 批量操作默认的最大并发数
+const DefaultConcurrency = 4
+
+var (
+	// ErrNotFound 账户不存在
+	ErrNotFound = errors.New("accounts: 账户不存在")
+	// ErrExists 账户已存在
+	ErrExists = errors.New("accounts: 账户已存在")
+)
+
+// Config Registry的配置，对所有账户生效
+type Config struct {
+	ClientOptions []odin_api.Option      // 创建每个账户的odin_api客户端时使用的选项
+	Session       odin_api.SessionConfig // 每个账户会话的配置
+	Agent         agent.Config           // 创建Agent的配置，Identity字段会被账户身份覆盖
+	CanisterID    string                 // agent_sdk使用的Canister ID，为空时使用默认值
+	Concurrency   int                    // 批量操作的最大并发数，默认DefaultConcurrency
+}
+
+// Account 单个账户
+type Account struct {
+	name     string
+	identity *odin_api.KeyIdentity
+	api      *odin_api.Client
+	session  *odin_api.Session
+	cfg      *Config
+
+	mu       sync.Mutex
+	canister *agent_sdk.Client
+}
+
+// Name 返回账户名称
+func (a *Account) Name() string {
+	return a.name
+}
+
+// Principal 返回账户的Principal文本
+func (a *Account) Principal() string {
+	return a.identity.PrincipalText()
+}
+
+// Identity 返回账户的身份
+func (a *Account) Identity() *odin_api.KeyIdentity {
+	return a.identity
+}
+
+// API 返回账户专用的odin_api客户端，请求会自动携带该账户的令牌
+func (a *Account) API() *odin_api.Client {
+	return a.api
+}
+
+// Session 返回账户的会话
+func (a *Account) Session() *odin_api.Session {
+	return a.session
+}
+
+// Authenticate 立即进行认证，而不是等到第一次请求时
+func (a *Account) Authenticate(ctx context.Context) error {
+	_, err := a.session.Token(ctx)
+	return err
+}
+
+// Canister 返回使用该账户身份的agent_sdk客户端，首次调用时创建
+func (a *Account) Canister() (*agent_sdk.Client, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.canister != nil {
+		return a.canister, nil
+	}
+
+	cfg := a.cfg.Agent
+	cfg.Identity = a.identity.AgentIdentity()
+	ag, err := agent.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建Agent失败: %w", err)
+	}
+	client, err := agent_sdk.New(ag, a.cfg.CanisterID)
+	if err != nil {
+		return nil, err
+	}
+	a.canister = client
+	return client, nil
+}
+
+// Registry 按名称管理多个账户，可并发使用
+type Registry struct {
+	cfg Config
+
+	mu       sync.RWMutex
+	accounts map[string]*Account
+}
+
+// New 创建一个空的账户注册表
+func New(cfg Config) *Registry {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultConcurrency
+	}
+	return &Registry{
+		cfg:      cfg,
+		accounts: make(map[string]*Account),
+	}
+}
+
+// Add 使用给定身份添加账户，name已存在时返回ErrExists
+func (r *Registry) Add(name string, identity *odin_api.KeyIdentity) (*Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.accounts[name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrExists, name)
+	}
+
+	a := r.newAccount(name, identity)
+	r.accounts[name] = a
+	return a, nil
+}
+
+// AddFromKeystore 从密钥库解密并添加账户，names为空时添加密钥库中的全部身份
+// 所有身份都解密成功且名称都不冲突时才会添加，否则注册表保持不变
+func (r *Registry) AddFromKeystore(store *keystore.Store, passphrase string, names ...string) error {
+	if len(names) == 0 {
+		for _, entry := range store.List() {
+			names = append(names, entry.Name)
+		}
+	}
+
+	identities := make([]*odin_api.KeyIdentity, len(names))
+	for i, name := range names {
+		identity, err := store.Get(name, passphrase)
+		if err != nil {
+			return fmt.Errorf("加载账户 %s 失败: %w", name, err)
+		}
+		identities[i] = identity
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, name := range names {
+		if _, ok := r.accounts[name]; ok || slices.Contains(names[:i], name) {
+			return fmt.Errorf("%w: %s", ErrExists, name)
+		}
+	}
+	for i, name := range names {
+		r.accounts[name] = r.newAccount(name, identities[i])
+	}
+	return nil
+}
+
+// newAccount 创建账户及其客户端和Session
+func (r *Registry) newAccount(name string, identity *odin_api.KeyIdentity) *Account {
+	api := odin_api.NewClient(r.cfg.ClientOptions...)
+	return &Account{
+		name:     name,
+		identity: identity,
+		api:      api,
+		session:  api.NewSession(identity, r.cfg.Session),
+		cfg:      &r.cfg,
+	}
+}
+
+// Get 按名称获取账户
+func (r *Registry) Get(name string) (*Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return a, nil
+}
+
+// Remove 移除账户
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.accounts, name)
+}
+
+// Names 返回所有账户名称，按字母顺序排列
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.accounts))
+	for name := range r.accounts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Accounts 返回所有账户，按名称排序
+func (r *Registry) Accounts() []*Account {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	accounts := make([]*Account, 0, len(r.accounts))
+	for _, a := range r.accounts {
+		accounts = append(accounts, a)
+	}
+	slices.SortFunc(accounts, func(x, y *Account) int {
+		return strings.Compare(x.name, y.name)
+	})
+	return accounts
+}
@@ -0,0 +1,120 @@
+package accounts_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/MrHat365/odin-go/accounts"
+	"github.com/MrHat365/odin-go/keystore"
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+// newStore 创建保存了names中各个身份的密钥库，口令都为pass
+func newStore(t *testing.T, names ...string) *keystore.Store {
+	t.Helper()
+
+	store, err := keystore.Open(filepath.Join(t.TempDir(), "keystore.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if _, err := store.Generate(name, "pass"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestAddFromKeystore(t *testing.T) {
+	store := newStore(t, "a", "b")
+	registry := accounts.New(accounts.Config{})
+
+	if err := registry.AddFromKeystore(store, "pass"); err != nil {
+		t.Fatalf("AddFromKeystore: %v", err)
+	}
+	if names := registry.Names(); !slices.Equal(names, []string{"a", "b"}) {
+		t.Errorf("Names = %v, want [a b]", names)
+	}
+	a, err := registry.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry := store.List()[0]; a.Principal() != entry.Principal {
+		t.Errorf("Principal = %s, want %s", a.Principal(), entry.Principal)
+	}
+}
+
+func TestAddFromKeystoreIsAllOrNothing(t *testing.T) {
+	store := newStore(t, "a", "b", "c")
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		registry := accounts.New(accounts.Config{})
+		if err := registry.AddFromKeystore(store, "wrong", "a", "b"); !errors.Is(err, keystore.ErrWrongPassphrase) {
+			t.Fatalf("err = %v, want ErrWrongPassphrase", err)
+		}
+		if names := registry.Names(); len(names) != 0 {
+			t.Errorf("Names = %v, want empty", names)
+		}
+	})
+
+	t.Run("existing account", func(t *testing.T) {
+		registry := accounts.New(accounts.Config{})
+		if err := registry.AddFromKeystore(store, "pass", "b"); err != nil {
+			t.Fatal(err)
+		}
+		if err := registry.AddFromKeystore(store, "pass", "a", "b", "c"); !errors.Is(err, accounts.ErrExists) {
+			t.Fatalf("err = %v, want ErrExists", err)
+		}
+		if names := registry.Names(); !slices.Equal(names, []string{"b"}) {
+			t.Errorf("Names = %v, want [b]", names)
+		}
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		registry := accounts.New(accounts.Config{})
+		if err := registry.AddFromKeystore(store, "pass", "a", "a"); !errors.Is(err, accounts.ErrExists) {
+			t.Fatalf("err = %v, want ErrExists", err)
+		}
+		if names := registry.Names(); len(names) != 0 {
+			t.Errorf("Names = %v, want empty", names)
+		}
+	})
+}
+
+func TestAccountsAuthenticateIndependently(t *testing.T) {
+	srv := odintest.NewServer(odintest.Config{})
+	defer srv.Close()
+	srv.AddToken(odin_api.TokenDetail{ID: "2jjj"})
+
+	registry := accounts.New(accounts.Config{ClientOptions: []odin_api.Option{odin_api.WithBaseURL(srv.BaseURL())}})
+	if err := registry.AddFromKeystore(newStore(t, "a", "b"), "pass"); err != nil {
+		t.Fatal(err)
+	}
+
+	results := accounts.ForEach(context.Background(), registry, func(ctx context.Context, a *accounts.Account) (string, error) {
+		return a.API().PostCommentCtx(ctx, "gm from "+a.Name(), a.Principal(), "2jjj")
+	})
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("PostComment: %v", r.Err)
+		}
+	}
+
+	users := make(map[string]string)
+	for _, c := range srv.Comments() {
+		users[c.Message] = c.User
+	}
+	for _, name := range []string{"a", "b"} {
+		a, err := registry.Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := users["gm from "+name]; got != a.Principal() {
+			t.Errorf("comment from %s posted as %q, want %s", name, got, a.Principal())
+		}
+	}
+}
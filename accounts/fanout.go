@@ -0,0 +1,104 @@
+package accounts
+
+import (
+	"context"
+	"sync"
+
+	"github.com/MrHat365/odin-go/amounts"
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+// Result 单个账户的操作结果
+type Result[T any] struct {
+	Account string
+	Value   T
+	Err     error
+}
+
+// ForEach 以有限的并发数对所有账户执行fn，结果按账户名称排序
+// 单个账户失败不会影响其他账户，错误记录在对应的Result中
+func ForEach[T any](ctx context.Context, r *Registry, fn func(ctx context.Context, a *Account) (T, error)) []Result[T] {
+	accounts := r.Accounts()
+	results := make([]Result[T], len(accounts))
+
+	sem := make(chan struct{}, r.cfg.Concurrency)
+	var wg sync.WaitGroup
+	for i, a := range accounts {
+		results[i].Account = a.name
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i].Value, results[i].Err = fn(ctx, a)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Balances 并发获取所有账户的代币余额
+func (r *Registry) Balances(ctx context.Context) []Result[[]odin_api.BalanceDetail] {
+	return ForEach(ctx, r, func(ctx context.Context, a *Account) ([]odin_api.BalanceDetail, error) {
+		var balances []odin_api.BalanceDetail
+		for balance, err := range a.api.AllBalances(ctx, a.Principal(), 0) {
+			if err != nil {
+				return nil, err
+			}
+			balances = append(balances, balance)
+		}
+		return balances, nil
+	})
+}
+
+// TokenTotal 某个代币在所有账户中的合计持仓
+type TokenTotal struct {
+	ID       string
+	Ticker   string
+	Name     string
+	Scale    int                // 代币的小数位数
+	Balance  amounts.TokenUnits // 所有账户余额之和
+	Accounts int                // 持有该代币的账户数量
+}
+
+// Portfolio 所有账户的合计持仓
+type Portfolio struct {
+	Tokens   map[string]*TokenTotal             // 按代币ID汇总
+	Accounts []Result[[]odin_api.BalanceDetail] // 每个账户的余额明细
+	Failed   map[string]error                   // 获取余额失败的账户
+}
+
+// Portfolio 汇总所有账户的代币余额，获取失败的账户记录在Failed中且不计入合计
+func (r *Registry) Portfolio(ctx context.Context) *Portfolio {
+	p := &Portfolio{
+		Tokens:   make(map[string]*TokenTotal),
+		Accounts: r.Balances(ctx),
+		Failed:   make(map[string]error),
+	}
+
+	for _, result := range p.Accounts {
+		if result.Err != nil {
+			p.Failed[result.Account] = result.Err
+			continue
+		}
+		for _, balance := range result.Value {
+			total, ok := p.Tokens[balance.ID]
+			if !ok {
+				total = &TokenTotal{ID: balance.ID, Ticker: balance.Ticker, Name: balance.Name, Scale: balance.Scale()}
+				p.Tokens[balance.ID] = total
+			}
+			total.Balance = total.Balance.Add(balance.Balance)
+			total.Accounts++
+		}
+	}
+	return p
+}
@@ -1,9 +1,14 @@
 package agent_sdk
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/MrHat365/odin-go/metrics"
+	"github.com/MrHat365/odin-go/tracing"
 	"github.com/aviate-labs/agent-go"
 	"github.com/aviate-labs/agent-go/principal"
 )
@@ -12,6 +17,17 @@ import (
 type Client struct {
 	Agent      *agent.Agent
 	CanisterID principal.Principal
+
+	// Logger 记录每次Canister调用的方法名、类型、耗时和错误，级别为Debug
+	// 参数和返回值不会被记录，为nil时不记录日志
+	Logger *slog.Logger
+
+	// Metrics 按方法名和类型记录Canister调用的次数和耗时，为nil时不记录
+	Metrics metrics.Recorder
+
+	// Tracer 为每次Canister调用创建span，属性包括Canister ID、方法名、类型、发送者Principal
+	// 以及调用涉及的代币ID，为nil时不创建span
+	Tracer tracing.Tracer
 }
 
 // DefaultCanisterID 是AgentSdk智能合约的默认Canister ID
@@ -43,17 +59,119 @@ func New(agent *agent.Agent, canisterID string) (*Client, error) {
 	}, nil
 }
 
+// query 在ctx的控制下发送查询请求，attrs为span的附加属性
+func (c *Client) query(ctx context.Context, methodName string, args []any, out []any, attrs ...tracing.Attribute) error {
+	return c.invoke(ctx, "query", methodName, attrs, func() error {
+		return c.Agent.Query(c.CanisterID, methodName, args, out)
+	})
+}
+
+// call 在ctx的控制下发送更新请求，attrs为span的附加属性
+func (c *Client) call(ctx context.Context, methodName string, args []any, out []any, attrs ...tracing.Attribute) error {
+	return c.invoke(ctx, "update", methodName, attrs, func() error {
+		return c.Agent.Call(c.CanisterID, methodName, args, out)
+	})
+}
+
+// tokenAttr 返回代币ID的span属性
+func tokenAttr(tokenID TokenID) tracing.Attribute {
+	return tracing.String(tracing.AttrTokenID, string(tokenID))
+}
+
+// invoke 在ctx的控制下执行fn，并记录调用日志、指标和span
+func (c *Client) invoke(ctx context.Context, kind, methodName string, extra []tracing.Attribute, fn func() error) (err error) {
+	if c.Tracer != nil {
+		spanAttrs := append([]tracing.Attribute{
+			tracing.String(tracing.AttrCanisterID, c.CanisterID.String()),
+			tracing.String(tracing.AttrCanisterMethod, methodName),
+			tracing.String(tracing.AttrCallKind, kind),
+			tracing.String(tracing.AttrPrincipal, c.Agent.Sender().String()),
+		}, extra...)
+		var span tracing.Span
+		ctx, span = c.Tracer.Start(ctx, "agent_sdk "+methodName, spanAttrs...)
+		defer func() { span.End(err) }()
+	}
+
+	run := runWithContext
+	if kind == "update" {
+		run = runSubmitted
+	}
+	start := time.Now()
+	err = run(ctx, fn)
+	latency := time.Since(start)
+
+	if c.Metrics != nil {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		method, callKind := metrics.L("method", methodName), metrics.L("kind", kind)
+		c.Metrics.Add(metrics.CanisterCallsTotal, 1, method, callKind, metrics.L("result", result))
+		c.Metrics.Observe(metrics.CanisterCallDuration, latency.Seconds(), method, callKind)
+	}
+
+	if c.Logger != nil && c.Logger.Enabled(ctx, slog.LevelDebug) {
+		attrs := []slog.Attr{
+			slog.String("canister", c.CanisterID.String()),
+			slog.String("method", methodName),
+			slog.String("kind", kind),
+			slog.Duration("latency", latency),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		c.Logger.LogAttrs(ctx, slog.LevelDebug, "agent_sdk调用", attrs...)
+	}
+	return err
+}
+
+// runWithContext 执行fn，若ctx先被取消或超时则立即返回ctx.Err()
+// agent-go不支持按调用传入context，因此被放弃的调用会在后台继续执行直至完成，
+// 其结果将被丢弃。只用于查询调用，更新调用使用runSubmitted
+func runWithContext(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runSubmitted 在ctx未结束时执行fn并等待其完成
+// 更新调用提交后可能已经在链上生效，放弃等待会让调用方误以为调用未执行而重复提交，
+// 因此ctx只决定是否提交，调用一旦提交就不会因ctx取消而提前返回
+func runSubmitted(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fn()
+}
+
 // GetBalance 获取账户的代币余额
 // arg0: 账户标识符
 // arg1: 账户类型
 // arg2: 代币ID
 func (c *Client) GetBalance(arg0, arg1 string, arg2 TokenID) (*TokenAmount, error) {
+	return c.GetBalanceCtx(context.Background(), arg0, arg1, arg2)
+}
+
+// GetBalanceCtx 与GetBalance相同，但使用ctx控制调用的取消和超时
+func (c *Client) GetBalanceCtx(ctx context.Context, arg0, arg1 string, arg2 TokenID) (*TokenAmount, error) {
 	// 组装参数
 	args := []any{arg0, arg1, arg2}
 
 	// 发送查询请求
 	var response TokenAmount
-	err := c.Agent.Query(c.CanisterID, "getBalance", args, []any{&response})
+	err := c.query(ctx, "getBalance", args, []any{&response}, tokenAttr(arg2))
 	if err != nil {
 		return nil, fmt.Errorf("GetBalance请求失败: %w", err)
 	}
@@ -64,12 +182,17 @@ func (c *Client) GetBalance(arg0, arg1 string, arg2 TokenID) (*TokenAmount, erro
 // GetLockedTokens 获取锁定的代币信息
 // arg0: 账户标识符
 func (c *Client) GetLockedTokens(arg0 string) (*LockedTokenState, error) {
+	return c.GetLockedTokensCtx(context.Background(), arg0)
+}
+
+// GetLockedTokensCtx 与GetLockedTokens相同，但使用ctx控制调用的取消和超时
+func (c *Client) GetLockedTokensCtx(ctx context.Context, arg0 string) (*LockedTokenState, error) {
 	// 组装参数
 	args := []any{arg0}
 
 	// 发送查询请求
 	var response LockedTokenState
-	err := c.Agent.Query(c.CanisterID, "getLockedTokens", args, []any{&response})
+	err := c.query(ctx, "getLockedTokens", args, []any{&response})
 	if err != nil {
 		return nil, fmt.Errorf("GetLockedTokens请求失败: %w", err)
 	}
@@ -81,12 +204,17 @@ func (c *Client) GetLockedTokens(arg0 string) (*LockedTokenState, error) {
 // arg0: 账户标识符
 // arg1: 操作ID
 func (c *Client) GetOperation(arg0 string, arg1 TokenAmount) (*OptionalValue[Operation], error) {
+	return c.GetOperationCtx(context.Background(), arg0, arg1)
+}
+
+// GetOperationCtx 与GetOperation相同，但使用ctx控制调用的取消和超时
+func (c *Client) GetOperationCtx(ctx context.Context, arg0 string, arg1 TokenAmount) (*OptionalValue[Operation], error) {
 	// 组装参数
 	args := []any{arg0, arg1}
 
 	// 发送查询请求
 	var response OptionalValue[Operation]
-	err := c.Agent.Query(c.CanisterID, "getOperation", args, []any{&response})
+	err := c.query(ctx, "getOperation", args, []any{&response})
 	if err != nil {
 		return nil, fmt.Errorf("GetOperation请求失败: %w", err)
 	}
@@ -98,12 +226,17 @@ func (c *Client) GetOperation(arg0 string, arg1 TokenAmount) (*OptionalValue[Ope
 // arg0: 起始ID
 // arg1: 结束ID
 func (c *Client) GetOperations(arg0, arg1 TokenAmount) ([]OperationAndId, error) {
+	return c.GetOperationsCtx(context.Background(), arg0, arg1)
+}
+
+// GetOperationsCtx 与GetOperations相同，但使用ctx控制调用的取消和超时
+func (c *Client) GetOperationsCtx(ctx context.Context, arg0, arg1 TokenAmount) ([]OperationAndId, error) {
 	// 组装参数
 	args := []any{arg0, arg1}
 
 	// 发送查询请求
 	var response []OperationAndId
-	err := c.Agent.Query(c.CanisterID, "getOperations", args, []any{&response})
+	err := c.query(ctx, "getOperations", args, []any{&response})
 	if err != nil {
 		return nil, fmt.Errorf("GetOperations请求失败: %w", err)
 	}
@@ -114,12 +247,17 @@ func (c *Client) GetOperations(arg0, arg1 TokenAmount) ([]OperationAndId, error)
 // GetStats 获取统计信息
 // arg0: 统计类型
 func (c *Client) GetStats(arg0 string) (map[string]string, error) {
+	return c.GetStatsCtx(context.Background(), arg0)
+}
+
+// GetStatsCtx 与GetStats相同，但使用ctx控制调用的取消和超时
+func (c *Client) GetStatsCtx(ctx context.Context, arg0 string) (map[string]string, error) {
 	// 组装参数
 	args := []any{arg0}
 
 	// 发送查询请求
 	var response map[string]string
-	err := c.Agent.Query(c.CanisterID, "getStats", args, []any{&response})
+	err := c.query(ctx, "getStats", args, []any{&response})
 	if err != nil {
 		return nil, fmt.Errorf("GetStats请求失败: %w", err)
 	}
@@ -131,12 +269,17 @@ func (c *Client) GetStats(arg0 string) (map[string]string, error) {
 // arg0: 账户标识符
 // tokenID: 代币ID
 func (c *Client) GetToken(arg0 string, tokenID TokenID) (*OptionalValue[Token], error) {
+	return c.GetTokenCtx(context.Background(), arg0, tokenID)
+}
+
+// GetTokenCtx 与GetToken相同，但使用ctx控制调用的取消和超时
+func (c *Client) GetTokenCtx(ctx context.Context, arg0 string, tokenID TokenID) (*OptionalValue[Token], error) {
 	// 组装参数
 	args := []any{arg0, tokenID}
 
 	// 发送查询请求
 	var response OptionalValue[Token]
-	err := c.Agent.Query(c.CanisterID, "getToken", args, []any{&response})
+	err := c.query(ctx, "getToken", args, []any{&response}, tokenAttr(tokenID))
 	if err != nil {
 		return nil, fmt.Errorf("GetToken请求失败: %w", err)
 	}
@@ -147,12 +290,17 @@ func (c *Client) GetToken(arg0 string, tokenID TokenID) (*OptionalValue[Token],
 // GetTokenIndex 获取代币索引
 // tokenID: 代币ID
 func (c *Client) GetTokenIndex(tokenID TokenID) (*TokenAmount, error) {
+	return c.GetTokenIndexCtx(context.Background(), tokenID)
+}
+
+// GetTokenIndexCtx 与GetTokenIndex相同，但使用ctx控制调用的取消和超时
+func (c *Client) GetTokenIndexCtx(ctx context.Context, tokenID TokenID) (*TokenAmount, error) {
 	// 组装参数
 	args := []any{tokenID}
 
 	// 发送查询请求
 	var response TokenAmount
-	err := c.Agent.Query(c.CanisterID, "getTokenIndex", args, []any{&response})
+	err := c.query(ctx, "getTokenIndex", args, []any{&response}, tokenAttr(tokenID))
 	if err != nil {
 		return nil, fmt.Errorf("GetTokenIndex请求失败: %w", err)
 	}
@@ -163,12 +311,18 @@ func (c *Client) GetTokenIndex(tokenID TokenID) (*TokenAmount, error) {
 // TokenAdd 添加代币
 // request: 添加代币请求
 func (c *Client) TokenAdd(request AddRequest) (*AddResponse, error) {
+	return c.TokenAddCtx(context.Background(), request)
+}
+
+// TokenAddCtx 与TokenAdd相同，ctx只在提交之前生效
+// ctx已被取消或超时时不会发送调用；调用一旦提交就会等待其完成，不会因ctx取消而提前返回
+func (c *Client) TokenAddCtx(ctx context.Context, request AddRequest) (*AddResponse, error) {
 	// 组装参数
 	args := []any{request}
 
 	// 发送更新请求
 	var response AddResponse
-	err := c.Agent.Call(c.CanisterID, "token_add", args, []any{&response})
+	err := c.call(ctx, "token_add", args, []any{&response}, tokenAttr(request.TokenID))
 	if err != nil {
 		return nil, fmt.Errorf("TokenAdd请求失败: %w", err)
 	}
@@ -180,12 +334,18 @@ func (c *Client) TokenAdd(request AddRequest) (*AddResponse, error) {
 // tokenID: 代币ID
 // amount: 存入金额
 func (c *Client) TokenDeposit(tokenID TokenID, amount TokenAmount) (*TokenAmount, error) {
+	return c.TokenDepositCtx(context.Background(), tokenID, amount)
+}
+
+// TokenDepositCtx 与TokenDeposit相同，ctx只在提交之前生效
+// ctx已被取消或超时时不会发送调用；调用一旦提交就会等待其完成，不会因ctx取消而提前返回
+func (c *Client) TokenDepositCtx(ctx context.Context, tokenID TokenID, amount TokenAmount) (*TokenAmount, error) {
 	// 组装参数
 	args := []any{tokenID, amount}
 
 	// 发送更新请求
 	var response TokenAmount
-	err := c.Agent.Call(c.CanisterID, "token_deposit", args, []any{&response})
+	err := c.call(ctx, "token_deposit", args, []any{&response}, tokenAttr(tokenID))
 	if err != nil {
 		return nil, fmt.Errorf("TokenDeposit请求失败: %w", err)
 	}
@@ -196,12 +356,18 @@ func (c *Client) TokenDeposit(tokenID TokenID, amount TokenAmount) (*TokenAmount
 // TokenEtch 铸造代币
 // request: 铸造代币请求
 func (c *Client) TokenEtch(request EtchRequest) (*EtchResponse, error) {
+	return c.TokenEtchCtx(context.Background(), request)
+}
+
+// TokenEtchCtx 与TokenEtch相同，ctx只在提交之前生效
+// ctx已被取消或超时时不会发送调用；调用一旦提交就会等待其完成，不会因ctx取消而提前返回
+func (c *Client) TokenEtchCtx(ctx context.Context, request EtchRequest) (*EtchResponse, error) {
 	// 组装参数
 	args := []any{request}
 
 	// 发送更新请求
 	var response EtchResponse
-	err := c.Agent.Call(c.CanisterID, "token_etch", args, []any{&response})
+	err := c.call(ctx, "token_etch", args, []any{&response}, tokenAttr(request.TokenID))
 	if err != nil {
 		return nil, fmt.Errorf("TokenEtch请求失败: %w", err)
 	}
@@ -212,12 +378,18 @@ func (c *Client) TokenEtch(request EtchRequest) (*EtchResponse, error) {
 // TokenLiquidity 处理代币流动性
 // request: 流动性请求
 func (c *Client) TokenLiquidity(request LiquidityRequest) (*LiquidityResponse, error) {
+	return c.TokenLiquidityCtx(context.Background(), request)
+}
+
+// TokenLiquidityCtx 与TokenLiquidity相同，ctx只在提交之前生效
+// ctx已被取消或超时时不会发送调用；调用一旦提交就会等待其完成，不会因ctx取消而提前返回
+func (c *Client) TokenLiquidityCtx(ctx context.Context, request LiquidityRequest) (*LiquidityResponse, error) {
 	// 组装参数
 	args := []any{request}
 
 	// 发送更新请求
 	var response LiquidityResponse
-	err := c.Agent.Call(c.CanisterID, "token_liquidity", args, []any{&response})
+	err := c.call(ctx, "token_liquidity", args, []any{&response}, tokenAttr(request.TokenID))
 	if err != nil {
 		return nil, fmt.Errorf("TokenLiquidity请求失败: %w", err)
 	}
@@ -228,12 +400,18 @@ func (c *Client) TokenLiquidity(request LiquidityRequest) (*LiquidityResponse, e
 // TokenMint 铸造代币到指定地址
 // request: 铸造请求
 func (c *Client) TokenMint(request MintRequest) (*MintResponse, error) {
+	return c.TokenMintCtx(context.Background(), request)
+}
+
+// TokenMintCtx 与TokenMint相同，ctx只在提交之前生效
+// ctx已被取消或超时时不会发送调用；调用一旦提交就会等待其完成，不会因ctx取消而提前返回
+func (c *Client) TokenMintCtx(ctx context.Context, request MintRequest) (*MintResponse, error) {
 	// 组装参数
 	args := []any{request}
 
 	// 发送更新请求
 	var response MintResponse
-	err := c.Agent.Call(c.CanisterID, "token_mint", args, []any{&response})
+	err := c.call(ctx, "token_mint", args, []any{&response}, tokenAttr(request.TokenID))
 	if err != nil {
 		return nil, fmt.Errorf("TokenMint请求失败: %w", err)
 	}
@@ -244,12 +422,18 @@ func (c *Client) TokenMint(request MintRequest) (*MintResponse, error) {
 // TokenTrade 交易代币
 // request: 交易请求
 func (c *Client) TokenTrade(request TradeRequest) (*TradeResponse, error) {
+	return c.TokenTradeCtx(context.Background(), request)
+}
+
+// TokenTradeCtx 与TokenTrade相同，ctx只在提交之前生效
+// ctx已被取消或超时时不会发送调用；调用一旦提交就会等待其完成，不会因ctx取消而提前返回
+func (c *Client) TokenTradeCtx(ctx context.Context, request TradeRequest) (*TradeResponse, error) {
 	// 组装参数
 	args := []any{request}
 
 	// 发送更新请求
 	var response TradeResponse
-	err := c.Agent.Call(c.CanisterID, "token_trade", args, []any{&response})
+	err := c.call(ctx, "token_trade", args, []any{&response}, tokenAttr(request.TokenID))
 	if err != nil {
 		return nil, fmt.Errorf("TokenTrade请求失败: %w", err)
 	}
@@ -260,12 +444,18 @@ func (c *Client) TokenTrade(request TradeRequest) (*TradeResponse, error) {
 // TokenWithdraw 提取代币
 // request: 提取请求
 func (c *Client) TokenWithdraw(request WithdrawRequest) (*WithdrawResponse, error) {
+	return c.TokenWithdrawCtx(context.Background(), request)
+}
+
+// TokenWithdrawCtx 与TokenWithdraw相同，ctx只在提交之前生效
+// ctx已被取消或超时时不会发送调用；调用一旦提交就会等待其完成，不会因ctx取消而提前返回
+func (c *Client) TokenWithdrawCtx(ctx context.Context, request WithdrawRequest) (*WithdrawResponse, error) {
 	// 组装参数
 	args := []any{request}
 
 	// 发送更新请求
 	var response WithdrawResponse
-	err := c.Agent.Call(c.CanisterID, "token_withdraw", args, []any{&response})
+	err := c.call(ctx, "token_withdraw", args, []any{&response}, tokenAttr(request.TokenID))
 	if err != nil {
 		return nil, fmt.Errorf("TokenWithdraw请求失败: %w", err)
 	}
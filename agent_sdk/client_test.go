@@ -0,0 +1,50 @@
+package agent_sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunWithContextReturnsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	start := time.Now()
+	err := runWithContext(ctx, func() error {
+		<-release
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("returned after %v, want about 10ms", elapsed)
+	}
+}
+
+func TestRunSubmittedWaitsForSubmittedCall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	called := false
+	err := runSubmitted(ctx, func() error {
+		// 提交之后取消不会让调用方提前返回
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		called = true
+		return nil
+	})
+	if err != nil || !called {
+		t.Fatalf("runSubmitted = %v, called = %v, want the call to complete", err, called)
+	}
+
+	called = false
+	if err := runSubmitted(ctx, func() error { called = true; return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("runSubmitted with canceled ctx = %v, want Canceled", err)
+	}
+	if called {
+		t.Error("call was submitted with a canceled ctx")
+	}
+}
@@ -105,30 +105,27 @@ func main() {
 	//}
 	//fmt.Println("创建AgentSdk客户端成功，使用身份:", principalID)
 	//
-	//// 保存密钥对到文件示例
-	//fmt.Println("\n===== 示例5: 保存和加载密钥对到文件 =====")
-	//keyFile := "example_keys.txt"
-	//err = os.WriteFile(keyFile, []byte(pubKeyHex+"\n"+privKeyHex), 0600)
+	//// 使用加密密钥库保存和加载身份示例
+	//fmt.Println("\n===== 示例5: 使用加密密钥库保存和加载身份 =====")
+	//store, err := keystore.Open("example_keystore.json")
 	//if err != nil {
-	//	log.Printf("保存密钥到文件失败: %v", err)
-	//} else {
-	//	fmt.Printf("密钥对已保存到文件: %s\n", keyFile)
+	//	log.Fatalf("打开密钥库失败: %v", err)
+	//}
+	//passphrase := os.Getenv("ODIN_KEYSTORE_PASSPHRASE")
+	//if _, err := store.ImportPEM("example", pemData, passphrase); err != nil {
+	//	log.Printf("保存身份失败: %v", err)
 	//}
 	//
-	//// 从文件加载密钥对示例
-	//fileContent, err := os.ReadFile(keyFile)
+	//// 从密钥库加载身份
+	//loaded, err := store.Get("example", passphrase)
 	//if err != nil {
-	//	log.Printf("从文件加载密钥失败: %v", err)
+	//	log.Printf("加载身份失败: %v", err)
 	//} else {
-	//	lines := strings.Split(string(fileContent), "\n")
-	//	if len(lines) >= 2 {
-	//		fmt.Printf("从文件加载的公钥: %s...\n", lines[0][:10])
-	//		fmt.Printf("从文件加载的私钥: %s...\n", lines[1][:10])
-	//	}
+	//	fmt.Printf("从密钥库加载的Principal ID: %s\n", loaded.PrincipalText())
 	//}
 	//
 	//// 删除测试文件
-	//_ = os.Remove(keyFile)
+	//_ = os.Remove("example_keystore.json")
 	//
 	//// 以下是原示例代码
 	//fmt.Println("\n===== AgentSdk客户端功能演示 =====")
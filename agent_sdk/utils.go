@@ -3,10 +3,14 @@ package agent_sdk
 import (
 	"math"
 	"math/big"
+
+	"github.com/MrHat365/odin-go/amounts"
 )
 
 // ConvertToBTC 将satoshis转换为BTC
 // 与C#版本对应，将satoshis值除以1000，并保留6位小数
+//
+// Deprecated: 结果为float64会丢失精度，使用amounts.MilliSats的BTC或Sats方法进行精确转换。
 func ConvertToBTC(satoshis int64) float64 {
 	// 转换为浮点数后除以1000
 	value := float64(satoshis) / 1000.0
@@ -16,11 +20,10 @@ func ConvertToBTC(satoshis int64) float64 {
 
 // ConvertToTokenAmount 将satoshis转换为代币数量
 // 将satoshis值除以100000000000并四舍五入
+//
+// Deprecated: 使用amounts.TokenUnits的Whole或Format方法，并传入代币实际的小数位数。
 func ConvertToTokenAmount(satoshis int64) int64 {
-	// 转换为浮点数后除以100000000000
-	value := float64(satoshis) / 100000000000.0
-	// 四舍五入到整数
-	return int64(math.Round(value))
+	return amounts.NewTokenUnits(satoshis).Whole(amounts.DefaultTokenScale).Int64()
 }
 
 // CalculatePercentDifference 计算两个数值之间的百分比差异
@@ -59,3 +62,19 @@ func TokenAmountToFloat64(amount *big.Int) float64 {
 	result, _ := f.Float64()
 	return result
 }
+
+// TokenAmountToUnits 将TokenAmount转换为amounts.TokenUnits
+func TokenAmountToUnits(amount TokenAmount) amounts.TokenUnits {
+	return amounts.TokenUnitsFromInt(amount)
+}
+
+// UnitsToTokenAmount 将amounts.TokenUnits转换为TokenAmount，用于构造Canister请求
+func UnitsToTokenAmount(units amounts.TokenUnits) TokenAmount {
+	return units.Int()
+}
+
+// FormatTokenAmount 按scale位小数将TokenAmount精确格式化为整数个代币，例如 "1.5"
+// Odin.fun代币的scale通常为amounts.DefaultTokenScale
+func FormatTokenAmount(amount TokenAmount, scale int) string {
+	return amounts.TokenUnitsFromInt(amount).Format(scale)
+}
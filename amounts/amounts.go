@@ -0,0 +1,163 @@
+// Package amounts 提供精确的BTC和代币数量类型
+//
+// Odin.fun API中的BTC金额以毫聪（millisatoshi）为单位，代币数量以最小单位表示，
+// 其小数位数为Divisibility与Decimals之和。所有类型都基于big.Int，
+// 不会因为超出int64或转换为float64而丢失精度。零值表示0，值不可变，可以安全地复制和共享。
+package amounts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MilliSatsPerSat 每聪的毫聪数
+	MilliSatsPerSat = 1000
+	// SatsPerBTC 每BTC的聪数
+	SatsPerBTC = 100_000_000
+	// BTCScale BTC相对于毫聪的小数位数
+	BTCScale = 11
+	// DefaultTokenScale Odin.fun代币默认的小数位数（Divisibility 8 + Decimals 3）
+	DefaultTokenScale = 11
+)
+
+// ErrInvalidAmount 无法解析的数量
+var ErrInvalidAmount = errors.New("无效的数量")
+
+// integer 各数量类型共用的不可变整数，nil表示0
+type integer struct {
+	n *big.Int
+}
+
+// value 返回底层整数，调用方不能修改返回值
+func (i integer) value() *big.Int {
+	if i.n == nil {
+		return new(big.Int)
+	}
+	return i.n
+}
+
+// Int 返回底层整数的副本
+func (i integer) Int() *big.Int {
+	return new(big.Int).Set(i.value())
+}
+
+// Int64 返回int64表示，超出范围时ok为false
+func (i integer) Int64() (v int64, ok bool) {
+	n := i.value()
+	return n.Int64(), n.IsInt64()
+}
+
+// Sign 返回-1、0或1
+func (i integer) Sign() int {
+	return i.value().Sign()
+}
+
+// IsZero 判断是否为0
+func (i integer) IsZero() bool {
+	return i.Sign() == 0
+}
+
+// String 返回十进制整数表示
+func (i integer) String() string {
+	return i.value().String()
+}
+
+// MarshalJSON 编码为JSON数字
+func (i integer) MarshalJSON() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalJSON 解析JSON数字或字符串，null解析为0
+func (i *integer) UnmarshalJSON(data []byte) error {
+	n, err := parseJSON(data, 0)
+	if err != nil {
+		return err
+	}
+	i.n = n
+	return nil
+}
+
+// parseJSON 解析JSON数字、字符串或null，scale为小数位数
+func parseJSON(data []byte, scale int) (*big.Int, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	return parseFixed(string(data), scale)
+}
+
+// decimalPattern 金额字符串的语法：十进制数，可带不超过3位数字的指数
+// big.Rat.SetString还接受分数和任意大的指数，必须先用该语法校验
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]{1,3})?$`)
+
+// parseFixed 将十进制字符串解析为放大10^scale倍的整数
+// 支持指数不超过3位的科学计数法，超出scale的非零小数位会返回错误而不是被截断
+func parseFixed(s string, scale int) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("%w: 空字符串", ErrInvalidAmount)
+	}
+	if !decimalPattern.MatchString(s) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(pow10(scale)))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("%w: %q 超过%d位小数", ErrInvalidAmount, s, scale)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// formatFixed 将整数n格式化为带scale位小数的十进制字符串，省略末尾的0
+func formatFixed(n *big.Int, scale int) string {
+	if scale <= 0 {
+		return new(big.Int).Mul(n, pow10(-scale)).String()
+	}
+
+	digits := new(big.Int).Abs(n).String()
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-scale], strings.TrimRight(digits[len(digits)-scale:], "0")
+
+	var b strings.Builder
+	if n.Sign() < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(whole)
+	if frac != "" {
+		b.WriteByte('.')
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// pow10 返回10^n，n小于0时返回1
+func pow10(n int) *big.Int {
+	if n <= 0 {
+		return big.NewInt(1)
+	}
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// add 返回a+b
+func add(a, b integer) *big.Int {
+	return new(big.Int).Add(a.value(), b.value())
+}
+
+// sub 返回a-b
+func sub(a, b integer) *big.Int {
+	return new(big.Int).Sub(a.value(), b.value())
+}
@@ -0,0 +1,156 @@
+package amounts_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/MrHat365/odin-go/amounts"
+)
+
+func TestParseBTC(t *testing.T) {
+	tests := []struct {
+		in        string
+		millisats string
+		str       string
+	}{
+		{"0.00012345", "12345000", "0.00012345"},
+		{"1", "100000000000", "1"},
+		{"-0.5", "-50000000000", "-0.5"},
+		{"0.00000000001", "1", "0.00000000001"},
+		{"1.5e-3", "150000000", "0.0015"},
+		{"21000000", "2100000000000000000", "21000000"},
+	}
+	for _, tt := range tests {
+		b, err := amounts.ParseBTC(tt.in)
+		if err != nil {
+			t.Errorf("ParseBTC(%q): %v", tt.in, err)
+			continue
+		}
+		if got := b.MilliSats().String(); got != tt.millisats {
+			t.Errorf("ParseBTC(%q) = %s millisats, want %s", tt.in, got, tt.millisats)
+		}
+		if got := b.String(); got != tt.str {
+			t.Errorf("ParseBTC(%q).String() = %s, want %s", tt.in, got, tt.str)
+		}
+	}
+}
+
+func TestParseRejectsInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"abc",
+		"6/3",
+		"0x10",
+		"+5",
+		"1.",
+		".5",
+		"1e1000000000",
+		"1e-1000",
+		"0.000000000001", // 超过11位小数
+	} {
+		if _, err := amounts.ParseBTC(in); !errors.Is(err, amounts.ErrInvalidAmount) {
+			t.Errorf("ParseBTC(%q) err = %v, want ErrInvalidAmount", in, err)
+		}
+	}
+	if _, err := amounts.ParseMilliSats("1.5"); !errors.Is(err, amounts.ErrInvalidAmount) {
+		t.Errorf("ParseMilliSats(1.5) err = %v, want ErrInvalidAmount", err)
+	}
+}
+
+func TestTokenUnits(t *testing.T) {
+	units, err := amounts.ParseTokenUnits("1234.5", amounts.DefaultTokenScale)
+	if err != nil {
+		t.Fatalf("ParseTokenUnits: %v", err)
+	}
+	if got := units.String(); got != "123450000000000" {
+		t.Errorf("units = %s, want 123450000000000", got)
+	}
+	if got := units.Format(amounts.DefaultTokenScale); got != "1234.5" {
+		t.Errorf("Format = %s, want 1234.5", got)
+	}
+	// Whole四舍五入到整数个代币
+	if got := units.Whole(amounts.DefaultTokenScale).String(); got != "1235" {
+		t.Errorf("Whole = %s, want 1235", got)
+	}
+	if got := amounts.NewTokenUnits(-149).Whole(2).String(); got != "-1" {
+		t.Errorf("Whole(-1.49) = %s, want -1", got)
+	}
+	if got := amounts.Scale(8, 3); got != 11 {
+		t.Errorf("Scale(8, 3) = %d, want 11", got)
+	}
+
+	sum := units.Add(amounts.NewTokenUnits(1))
+	if sum.Cmp(units) <= 0 || units.String() != "123450000000000" {
+		t.Errorf("Add changed the receiver or did not increase: %s, %s", sum, units)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		Price  amounts.MilliSats  `json:"price"`
+		Amount amounts.TokenUnits `json:"amount"`
+		Volume amounts.MilliSats  `json:"volume"`
+		Total  amounts.BTC        `json:"total"`
+	}
+	// 超出int64和float64精度的数值也能精确解析
+	data := `{"price":"123456789012345678901234567890","amount":98765432109876543210,"volume":null,"total":"0.00012345"}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got := v.Price.String(); got != "123456789012345678901234567890" {
+		t.Errorf("Price = %s", got)
+	}
+	if _, ok := v.Price.Int64(); ok {
+		t.Error("Price.Int64 ok = true, want false for values beyond int64")
+	}
+	if got := v.Amount.String(); got != "98765432109876543210" {
+		t.Errorf("Amount = %s", got)
+	}
+	if !v.Volume.IsZero() {
+		t.Errorf("Volume = %s, want 0 for null", v.Volume)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"price":123456789012345678901234567890,"amount":98765432109876543210,"volume":0,"total":0.00012345}`
+	if string(out) != want {
+		t.Errorf("Marshal = %s, want %s", out, want)
+	}
+
+	var bad amounts.MilliSats
+	if err := json.Unmarshal([]byte(`"6/3"`), &bad); !errors.Is(err, amounts.ErrInvalidAmount) {
+		t.Errorf("Unmarshal(\"6/3\") err = %v, want ErrInvalidAmount", err)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	m := amounts.NewMilliSats(123_456_789)
+	if got := m.Sats().String(); got != "123456" {
+		t.Errorf("Sats = %s, want 123456", got)
+	}
+	if got := amounts.NewSats(1).MilliSats().String(); got != "1000" {
+		t.Errorf("NewSats(1).MilliSats = %s, want 1000", got)
+	}
+	if got := amounts.NewSats(amounts.SatsPerBTC).BTC().String(); got != "1" {
+		t.Errorf("1e8 sats = %s BTC, want 1", got)
+	}
+
+	// nil视为0，传入的big.Int被复制
+	if !amounts.MilliSatsFromInt(nil).IsZero() || !amounts.SatsFromInt(nil).IsZero() || !amounts.TokenUnitsFromInt(nil).IsZero() {
+		t.Error("FromInt(nil) is not zero")
+	}
+	n := big.NewInt(5)
+	s := amounts.SatsFromInt(n)
+	n.SetInt64(6)
+	if got := s.String(); got != "5" {
+		t.Errorf("SatsFromInt shares its argument: %s", got)
+	}
+	var zero amounts.BTC
+	if got := zero.Add(amounts.NewMilliSats(1).BTC()).String(); got != "0.00000000001" {
+		t.Errorf("zero.Add = %s", got)
+	}
+}
@@ -0,0 +1,180 @@
+package amounts
+
+import (
+	"math/big"
+)
+
+// MilliSats 以毫聪为单位的BTC金额，Odin.fun API中价格、交易额和BTC余额使用的单位
+type MilliSats struct {
+	integer
+}
+
+// NewMilliSats 从int64创建MilliSats
+func NewMilliSats(v int64) MilliSats {
+	return MilliSats{integer{big.NewInt(v)}}
+}
+
+// MilliSatsFromInt 从big.Int创建MilliSats，n会被复制，nil视为0
+func MilliSatsFromInt(n *big.Int) MilliSats {
+	if n == nil {
+		return MilliSats{}
+	}
+	return MilliSats{integer{new(big.Int).Set(n)}}
+}
+
+// ParseMilliSats 解析十进制整数形式的毫聪数
+func ParseMilliSats(s string) (MilliSats, error) {
+	n, err := parseFixed(s, 0)
+	if err != nil {
+		return MilliSats{}, err
+	}
+	return MilliSats{integer{n}}, nil
+}
+
+// Add 返回m+o
+func (m MilliSats) Add(o MilliSats) MilliSats {
+	return MilliSats{integer{add(m.integer, o.integer)}}
+}
+
+// Sub 返回m-o
+func (m MilliSats) Sub(o MilliSats) MilliSats {
+	return MilliSats{integer{sub(m.integer, o.integer)}}
+}
+
+// Cmp 比较m和o，返回-1、0或1
+func (m MilliSats) Cmp(o MilliSats) int {
+	return m.value().Cmp(o.value())
+}
+
+// Sats 转换为聪，不足1聪的部分向零截断
+func (m MilliSats) Sats() Sats {
+	return Sats{integer{new(big.Int).Quo(m.value(), big.NewInt(MilliSatsPerSat))}}
+}
+
+// BTC 转换为BTC，不会丢失精度
+func (m MilliSats) BTC() BTC {
+	return BTC{m.integer}
+}
+
+// Sats 以聪为单位的BTC金额
+type Sats struct {
+	integer
+}
+
+// NewSats 从int64创建Sats
+func NewSats(v int64) Sats {
+	return Sats{integer{big.NewInt(v)}}
+}
+
+// SatsFromInt 从big.Int创建Sats，n会被复制，nil视为0
+func SatsFromInt(n *big.Int) Sats {
+	if n == nil {
+		return Sats{}
+	}
+	return Sats{integer{new(big.Int).Set(n)}}
+}
+
+// ParseSats 解析十进制整数形式的聪数
+func ParseSats(s string) (Sats, error) {
+	n, err := parseFixed(s, 0)
+	if err != nil {
+		return Sats{}, err
+	}
+	return Sats{integer{n}}, nil
+}
+
+// Add 返回s+o
+func (s Sats) Add(o Sats) Sats {
+	return Sats{integer{add(s.integer, o.integer)}}
+}
+
+// Sub 返回s-o
+func (s Sats) Sub(o Sats) Sats {
+	return Sats{integer{sub(s.integer, o.integer)}}
+}
+
+// Cmp 比较s和o，返回-1、0或1
+func (s Sats) Cmp(o Sats) int {
+	return s.value().Cmp(o.value())
+}
+
+// MilliSats 转换为毫聪
+func (s Sats) MilliSats() MilliSats {
+	return MilliSats{integer{new(big.Int).Mul(s.value(), big.NewInt(MilliSatsPerSat))}}
+}
+
+// BTC 转换为BTC
+func (s Sats) BTC() BTC {
+	return s.MilliSats().BTC()
+}
+
+// BTC 以BTC为单位的金额，内部以毫聪保存，最多11位小数
+type BTC struct {
+	integer
+}
+
+// ParseBTC 解析十进制形式的BTC金额，例如 "0.00012345"
+// 超过11位的非零小数会返回错误
+func ParseBTC(s string) (BTC, error) {
+	n, err := parseFixed(s, BTCScale)
+	if err != nil {
+		return BTC{}, err
+	}
+	return BTC{integer{n}}, nil
+}
+
+// Add 返回b+o
+func (b BTC) Add(o BTC) BTC {
+	return BTC{integer{add(b.integer, o.integer)}}
+}
+
+// Sub 返回b-o
+func (b BTC) Sub(o BTC) BTC {
+	return BTC{integer{sub(b.integer, o.integer)}}
+}
+
+// Cmp 比较b和o，返回-1、0或1
+func (b BTC) Cmp(o BTC) int {
+	return b.value().Cmp(o.value())
+}
+
+// MilliSats 转换为毫聪
+func (b BTC) MilliSats() MilliSats {
+	return MilliSats{b.integer}
+}
+
+// Sats 转换为聪，不足1聪的部分向零截断
+func (b BTC) Sats() Sats {
+	return b.MilliSats().Sats()
+}
+
+// Rat 返回精确的有理数表示
+func (b BTC) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(b.value(), pow10(BTCScale))
+}
+
+// Float64 返回最接近的float64，仅用于显示或近似计算
+func (b BTC) Float64() float64 {
+	f, _ := b.Rat().Float64()
+	return f
+}
+
+// String 返回十进制表示，省略末尾的0，例如 "0.00012345"
+func (b BTC) String() string {
+	return formatFixed(b.value(), BTCScale)
+}
+
+// MarshalJSON 编码为十进制JSON数字
+func (b BTC) MarshalJSON() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalJSON 解析十进制JSON数字或字符串，null解析为0
+func (b *BTC) UnmarshalJSON(data []byte) error {
+	n, err := parseJSON(data, BTCScale)
+	if err != nil {
+		return err
+	}
+	b.n = n
+	return nil
+}
@@ -0,0 +1,86 @@
+package amounts
+
+import (
+	"math/big"
+)
+
+// TokenUnits 以最小单位表示的代币数量
+//
+// 整数个代币等于TokenUnits除以10^scale，其中scale为代币的Divisibility与Decimals之和，
+// 可以通过Scale计算。JSON中按最小单位的整数编码。
+type TokenUnits struct {
+	integer
+}
+
+// Scale 返回代币的小数位数
+func Scale(divisibility, decimals int) int {
+	return divisibility + decimals
+}
+
+// NewTokenUnits 从int64创建TokenUnits
+func NewTokenUnits(v int64) TokenUnits {
+	return TokenUnits{integer{big.NewInt(v)}}
+}
+
+// TokenUnitsFromInt 从big.Int创建TokenUnits，n会被复制，nil视为0
+func TokenUnitsFromInt(n *big.Int) TokenUnits {
+	if n == nil {
+		return TokenUnits{}
+	}
+	return TokenUnits{integer{new(big.Int).Set(n)}}
+}
+
+// ParseTokenUnits 解析以整数个代币表示的十进制数量，例如 "1.5"
+// scale为代币的小数位数，超过scale的非零小数会返回错误
+func ParseTokenUnits(s string, scale int) (TokenUnits, error) {
+	n, err := parseFixed(s, scale)
+	if err != nil {
+		return TokenUnits{}, err
+	}
+	return TokenUnits{integer{n}}, nil
+}
+
+// Add 返回t+o
+func (t TokenUnits) Add(o TokenUnits) TokenUnits {
+	return TokenUnits{integer{add(t.integer, o.integer)}}
+}
+
+// Sub 返回t-o
+func (t TokenUnits) Sub(o TokenUnits) TokenUnits {
+	return TokenUnits{integer{sub(t.integer, o.integer)}}
+}
+
+// Cmp 比较t和o，返回-1、0或1
+func (t TokenUnits) Cmp(o TokenUnits) int {
+	return t.value().Cmp(o.value())
+}
+
+// Format 按scale位小数格式化为整数个代币，省略末尾的0，例如 "1.5"
+func (t TokenUnits) Format(scale int) string {
+	return formatFixed(t.value(), scale)
+}
+
+// Rat 返回以整数个代币表示的精确有理数
+func (t TokenUnits) Rat(scale int) *big.Rat {
+	return new(big.Rat).SetFrac(t.value(), pow10(scale))
+}
+
+// Float64 返回以整数个代币表示的最接近的float64，仅用于显示或近似计算
+func (t TokenUnits) Float64(scale int) float64 {
+	f, _ := t.Rat(scale).Float64()
+	return f
+}
+
+// Whole 返回整数个代币的数量，小数部分四舍五入
+func (t TokenUnits) Whole(scale int) *big.Int {
+	n, unit := t.value(), pow10(scale)
+	q, r := new(big.Int).QuoRem(n, unit, new(big.Int))
+	if r.Lsh(r.Abs(r), 1).Cmp(unit) >= 0 {
+		if n.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
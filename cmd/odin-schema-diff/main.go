@@ -0,0 +1,133 @@
+// odin-schema-diff 比较Odin.fun API的实时或录制响应与odin_api中的Go结构体
+//
+// 用法:
+//
+//	odin-schema-diff -endpoint /tokens                           # 请求实时API
+//	odin-schema-diff -endpoint /token/2jjj -file response.json   # 检查保存的响应体
+//	odin-schema-diff -cassette testdata/session.json             # 检查odintest录像中的所有响应
+//
+// 发现差异时退出码为1，参数或请求错误时为2。
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "", "相对于基础URL的端点，例如 /tokens 或 /token/{id}")
+	file := flag.String("file", "", "从文件读取响应体，而不是请求实时API（需要同时指定-endpoint）")
+	cassette := flag.String("cassette", "", "检查odintest录像文件中的所有成功响应")
+	baseURL := flag.String("base", odin_api.BaseURL, "API的基础URL")
+	timeout := flag.Duration("timeout", 30*time.Second, "实时请求的超时时间")
+	flag.Parse()
+
+	var (
+		drifted bool
+		err     error
+	)
+	switch {
+	case *cassette != "":
+		drifted, err = checkCassette(*cassette, *baseURL)
+	case *endpoint != "" && *file != "":
+		var body []byte
+		if body, err = os.ReadFile(*file); err == nil {
+			drifted, err = check(*endpoint, body)
+		}
+	case *endpoint != "":
+		client := odin_api.NewClient(odin_api.WithBaseURL(*baseURL), odin_api.WithTimeout(*timeout))
+		var body []byte
+		if body, err = client.GetCtx(context.Background(), *endpoint); err == nil {
+			drifted, err = check(*endpoint, body)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "错误:", err)
+		os.Exit(2)
+	}
+	if drifted {
+		os.Exit(1)
+	}
+}
+
+// check 比较一个响应与端点对应的结构体并输出差异，返回是否存在差异
+func check(endpoint string, body []byte) (bool, error) {
+	v, ok := odin_api.ResponseFor(endpoint)
+	if !ok {
+		return false, fmt.Errorf("未知的端点: %s", endpoint)
+	}
+
+	drift, err := odin_api.CheckSchema(body, v)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", endpoint, err)
+	}
+	if drift.Empty() {
+		fmt.Printf("%s (%s) 无差异\n", endpoint, drift.Type)
+		return false, nil
+	}
+
+	fmt.Printf("%s (%s)\n", endpoint, drift.Type)
+	for _, path := range drift.Unknown {
+		fmt.Printf("  + %s\n", path)
+	}
+	for _, path := range drift.Missing {
+		fmt.Printf("  - %s\n", path)
+	}
+	return true, nil
+}
+
+// checkCassette 检查录像中所有2xx且端点已知的响应
+func checkCassette(path, baseURL string) (bool, error) {
+	cassette, err := odintest.LoadCassette(path)
+	if err != nil {
+		return false, err
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return false, fmt.Errorf("无效的基础URL: %w", err)
+	}
+
+	var drifted bool
+	for _, interaction := range cassette.Interactions {
+		if interaction.Response.StatusCode < 200 || interaction.Response.StatusCode > 299 {
+			continue
+		}
+		u, err := url.Parse(interaction.Request.URL)
+		if err != nil {
+			return drifted, fmt.Errorf("无效的请求URL %q: %w", interaction.Request.URL, err)
+		}
+
+		endpoint := endpointOf(u, base.Path)
+		if _, ok := odin_api.ResponseFor(endpoint); !ok {
+			continue
+		}
+		d, err := check(endpoint, []byte(interaction.Response.Body))
+		if err != nil {
+			return drifted, err
+		}
+		drifted = drifted || d
+	}
+	return drifted, nil
+}
+
+// endpointOf 返回URL相对于基础路径的端点，录像可能来自其他主机（例如odintest），
+// 因此只比较路径
+func endpointOf(u *url.URL, basePath string) string {
+	path := strings.TrimPrefix(u.Path, strings.TrimRight(basePath, "/"))
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
@@ -2,14 +2,10 @@ package main
 
 import (
 	"bytes"
-	"crypto/ed25519"
-	"crypto/rand"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"github.com/MrHat365/odin-go/amounts"
 	"github.com/MrHat365/odin-go/odin_api"
-	"github.com/aviate-labs/agent-go/identity"
-	"github.com/aviate-labs/agent-go/principal"
 	"log"
 	"time"
 )
@@ -31,78 +27,18 @@ func PrettyPrint(v interface{}) {
 	fmt.Println(out.String())
 }
 
-// IdentityAdapter 适配器结构体，用于桥接 Ed25519Identity 和 auth.Identity
-type IdentityAdapter struct {
-	*identity.Ed25519Identity
-}
-
-// GetPublicKey 实现 auth.Identity 接口
-func (a *IdentityAdapter) GetPublicKey() []byte {
-	return a.PublicKey()
-}
-
-// Sign 实现 auth.Identity 接口
-func (a *IdentityAdapter) Sign(message []byte) ([]byte, error) {
-	return a.Ed25519Identity.Sign(message), nil
-}
-
-// generateKeys 生成新的Ed25519密钥对
-func generateKeys() (publicKey, privateKey []byte, err error) {
-	// 使用crypto/ed25519包生成新的密钥对
-	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
-	if err != nil {
-		return nil, nil, err
-	}
-	return pubKey, privKey, nil
-}
-
-// loadKeys 从字符串加载密钥对
-func loadKeys(pubKeyHex, privKeyHex string) (publicKey, privateKey []byte, err error) {
-	pubKey, err := hex.DecodeString(pubKeyHex)
-	if err != nil {
-		return nil, nil, fmt.Errorf("解析公钥失败: %w", err)
-	}
-
-	privKey, err := hex.DecodeString(privKeyHex)
-	if err != nil {
-		return nil, nil, fmt.Errorf("解析私钥失败: %w", err)
-	}
-
-	return pubKey, privKey, nil
-}
-
-// saveKeys 将密钥对保存为十六进制字符串
-func saveKeys(publicKey, privateKey []byte) (pubKeyHex, privKeyHex string) {
-	return hex.EncodeToString(publicKey), hex.EncodeToString(privateKey)
-}
-
-// createPrincipalFromPublicKey 从公钥创建principal ID
-func createPrincipalFromPublicKey(publicKey []byte) (string, error) {
-	// 使用公钥创建principal
-	p := principal.NewSelfAuthenticating(publicKey)
-	return p.String(), nil
-}
-
 func main() {
 	// 创建新的 Odin API 客户端
 	client := odin_api.NewClient()
 
-	pubKeyHex := "5d67c2887b8262c91af231d1a0b903efaebeb6e77dc45b8c898180228bb09318"
 	privKeyHex := "3d4f2c109c3e2532a17119f9d136bcbf360744fba39601afbcee434b3525dd125d67c2887b8262c91af231d1a0b903efaebeb6e77dc45b8c898180228bb09318"
-	loadedPubKey, loadedPrivKey, err := loadKeys(pubKeyHex, privKeyHex)
-	if err != nil {
-		log.Fatalf("加载密钥失败: %v", err)
-	}
-	log.Println(loadedPubKey)
-	log.Println(loadedPrivKey)
-	id, err := identity.NewEd25519Identity(loadedPubKey, loadedPrivKey)
+	id, err := odin_api.NewEd25519IdentityFromHex(privKeyHex)
 	if err != nil {
 		log.Fatalf("创建身份失败: %v", err)
 	}
 
 	// 演示 1: 创建身份并进行身份验证
-	identityAdapter := IdentityAdapter{id}
-	toekn := authenticateDemo(identityAdapter)
+	toekn := authenticateDemo(id)
 
 	client.SetToken(toekn)
 
@@ -113,10 +49,7 @@ func main() {
 	//topTokens := getTopTokensDemo(client)
 	//
 	//// 演示 4: 获取用户信息
-	principalID, err := createPrincipalFromPublicKey(loadedPubKey)
-	if err != nil {
-		log.Fatalf("获取Principal ID失败: %v", err)
-	}
+	principalID := id.PrincipalText()
 	fmt.Printf("Principal ID: %s\n", principalID)
 	getUserInfoDemo(client, principalID)
 
@@ -141,8 +74,8 @@ func main() {
 
 // authenticateDemo 演示如何创建身份并进行身份验证
 // 返回创建的身份、授权令牌和用户ID
-func authenticateDemo(id IdentityAdapter) string {
-	token, err := odin_api.AuthIdentity(&id)
+func authenticateDemo(id odin_api.Identity) string {
+	token, err := odin_api.AuthIdentity(id)
 	if err != nil {
 		log.Fatalf("%v", err)
 	}
@@ -193,13 +126,10 @@ func getTopTokensDemo(client *odin_api.Client) []odin_api.TokenDetail {
 
 	for i := 0; i < limit; i++ {
 		token := tokens.Data[i]
-		// 计算实际价格（考虑代币的小数位数）
-		price := float64(token.Price) / float64(10^token.Decimals)
-		marketcap := float64(token.Marketcap) / float64(10^token.Decimals)
-
+		// 价格和市值以毫聪为单位，转换为BTC显示
 		fmt.Printf("%d. %s (%s)\n", i+1, token.Name, token.Ticker)
-		fmt.Printf("   价格: $%.6f\n", price)
-		fmt.Printf("   市值: $%.2f\n", marketcap)
+		fmt.Printf("   价格: %s BTC\n", token.Price.BTC())
+		fmt.Printf("   市值: %s BTC\n", token.Marketcap.BTC())
 		fmt.Printf("   持有者数量: %d\n", token.HolderCount)
 		fmt.Printf("   ID: %s\n", token.ID)
 		fmt.Println()
@@ -236,11 +166,9 @@ func getUserBalancesDemo(client *odin_api.Client, principalID string) {
 
 	// 显示用户持有的代币余额
 	for i, balance := range balances.Data {
-		// 计算实际余额（考虑代币的小数位数）
-		actualBalance := float64(balance.Balance) / float64(10^balance.Decimals)
-
+		// 按代币的小数位数格式化余额
 		fmt.Printf("%d. %s (%s)\n", i+1, balance.Name, balance.Ticker)
-		fmt.Printf("   余额: %.8f\n", actualBalance)
+		fmt.Printf("   余额: %s\n", balance.Balance.Format(balance.Scale()))
 		fmt.Printf("   代币ID: %s\n", balance.ID)
 		fmt.Println()
 	}
@@ -257,15 +185,11 @@ func getTokenDetailsDemo(client *odin_api.Client, tokenID string) {
 		return
 	}
 
-	// 计算实际价格（考虑代币的小数位数）
-	price := float64(token.Price) / float64(10^token.Decimals)
-	marketcap := float64(token.Marketcap) / float64(10^token.Decimals)
-
 	fmt.Printf("名称: %s (%s)\n", token.Name, token.Ticker)
 	fmt.Printf("描述: %s\n", token.Description)
-	fmt.Printf("价格: $%.6f\n", price)
-	fmt.Printf("市值: $%.2f\n", marketcap)
-	fmt.Printf("总供应量: %d\n", token.TotalSupply)
+	fmt.Printf("价格: %s BTC\n", token.Price.BTC())
+	fmt.Printf("市值: %s BTC\n", token.Marketcap.BTC())
+	fmt.Printf("总供应量: %s\n", token.TotalSupply.Format(token.Scale()))
 	fmt.Printf("持有者数量: %d\n", token.HolderCount)
 	fmt.Printf("创建者: %s\n", token.Creator)
 	fmt.Printf("创建时间: %s\n", token.CreatedTime.Format(time.RFC3339))
@@ -298,7 +222,7 @@ func getTokenHoldersDemo(client *odin_api.Client, tokenID string) {
 	for i, holder := range holders.Data {
 		fmt.Printf("%d. 用户名: %s\n", i+1, holder.UserUsername)
 		fmt.Printf("   用户ID: %s\n", holder.User)
-		fmt.Printf("   持有量: %d\n", holder.Balance)
+		fmt.Printf("   持有量: %s\n", holder.Balance.Format(amounts.DefaultTokenScale))
 		fmt.Println()
 	}
 }
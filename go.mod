@@ -2,18 +2,22 @@ module github.com/MrHat365/odin-go
 
 go 1.24
 
+require (
+	github.com/aviate-labs/agent-go v0.7.2
+	github.com/aviate-labs/secp256k1 v0.0.0-5e6736a
+	golang.org/x/crypto v0.36.0
+)
+
 require (
 	github.com/0x51-dev/upeg v0.1.5 // indirect
-	github.com/aviate-labs/agent-go v0.7.2 // indirect
 	github.com/aviate-labs/leb128 v0.3.0 // indirect
-	github.com/aviate-labs/secp256k1 v0.0.0-5e6736a // indirect
 	github.com/bits-and-blooms/bitset v1.20.0 // indirect
 	github.com/consensys/bavard v0.1.27 // indirect
 	github.com/consensys/gnark-crypto v0.15.0 // indirect
 	github.com/fxamacker/cbor/v2 v2.7.0 // indirect
 	github.com/mmcloughlin/addchain v0.4.0 // indirect
 	github.com/x448/float16 v0.8.4 // indirect
-	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/sys v0.31.0 // indirect
 	google.golang.org/protobuf v1.36.3 // indirect
 	rsc.io/tmplfunc v0.0.3 // indirect
 )
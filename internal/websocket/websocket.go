@@ -0,0 +1,132 @@
+// Package websocket 实现feed客户端和odintest推送服务器共用的WebSocket帧编解码与握手密钥计算（RFC 6455）
+package websocket
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// GUID RFC 6455中用于计算Sec-WebSocket-Accept的固定GUID
+const GUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket帧的操作码
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// ErrFrameTooLarge 帧的负载超过读取方允许的大小
+var ErrFrameTooLarge = errors.New("WebSocket帧过大")
+
+// Frame 一个WebSocket帧，Payload已去除掩码
+type Frame struct {
+	Fin     bool
+	Opcode  byte
+	Masked  bool
+	Payload []byte
+}
+
+// AcceptKey 计算握手密钥对应的Sec-WebSocket-Accept值
+func AcceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + GUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// ReadFrame 从r读取一个帧，负载超过maxSize时返回ErrFrameTooLarge
+func ReadFrame(r io.Reader, maxSize uint64) (Frame, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return Frame{}, err
+	}
+	f := Frame{
+		Fin:    head[0]&0x80 != 0,
+		Opcode: head[0] & 0x0F,
+		Masked: head[1]&0x80 != 0,
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return Frame{}, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return Frame{}, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxSize {
+		return Frame{}, ErrFrameTooLarge
+	}
+
+	var mask [4]byte
+	if f.Masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return Frame{}, err
+		}
+	}
+
+	f.Payload = make([]byte, length)
+	if _, err := io.ReadFull(r, f.Payload); err != nil {
+		return Frame{}, err
+	}
+	if f.Masked {
+		for i := range f.Payload {
+			f.Payload[i] ^= mask[i%4]
+		}
+	}
+	return f, nil
+}
+
+// WriteFrame 向w写入一个FIN置位的完整帧
+// 客户端发送的帧必须加掩码（masked为true），服务器发送的帧不能加掩码
+func WriteFrame(w io.Writer, opcode byte, payload []byte, masked bool) error {
+	var maskBit byte
+	if masked {
+		maskBit = 0x80
+	}
+
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|opcode)
+
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	if masked {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return fmt.Errorf("生成帧掩码失败: %w", err)
+		}
+		frame = append(frame, mask[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	_, err := w.Write(frame)
+	return err
+}
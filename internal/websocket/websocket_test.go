@@ -0,0 +1,43 @@
+package websocket_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MrHat365/odin-go/internal/websocket"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// RFC 6455第1.3节的示例
+	if got := websocket.AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("AcceptKey = %s, want s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", got)
+	}
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 125, 126, 0xFFFF, 0x10000} {
+		for _, masked := range []bool{false, true} {
+			payload := bytes.Repeat([]byte{'x'}, size)
+			var buf bytes.Buffer
+			if err := websocket.WriteFrame(&buf, websocket.OpText, payload, masked); err != nil {
+				t.Fatalf("WriteFrame(%d, masked=%v): %v", size, masked, err)
+			}
+			frame, err := websocket.ReadFrame(&buf, 1<<20)
+			if err != nil {
+				t.Fatalf("ReadFrame(%d, masked=%v): %v", size, masked, err)
+			}
+			if !frame.Fin || frame.Opcode != websocket.OpText || frame.Masked != masked || !bytes.Equal(frame.Payload, payload) {
+				t.Errorf("frame(%d, masked=%v) = {Fin:%v Opcode:%d Masked:%v len:%d}", size, masked, frame.Fin, frame.Opcode, frame.Masked, len(frame.Payload))
+			}
+		}
+	}
+}
+
+func TestReadFrameTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	websocket.WriteFrame(&buf, websocket.OpBinary, make([]byte, 200), false)
+	if _, err := websocket.ReadFrame(&buf, 100); !errors.Is(err, websocket.ErrFrameTooLarge) {
+		t.Errorf("err = %v, want ErrFrameTooLarge", err)
+	}
+}
@@ -0,0 +1,387 @@
+// Package keystore 提供加密保存在磁盘上的交易身份
+//
+// 每个身份的PEM私钥使用scrypt从口令派生的密钥进行AES-256-GCM加密，
+// 以JSON格式保存在单个文件中，文件权限为0600。
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/aviate-labs/agent-go/identity"
+	"golang.org/x/crypto/scrypt"
+)
+
+// 文件格式版本
+const fileVersion = 1
+
+// 默认的scrypt参数
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltSize     = 16
+)
+
+// 从文件读取的scrypt参数的上限，防止损坏或恶意的密钥库消耗过多内存和CPU
+const (
+	maxScryptN      = 1 << 20
+	maxScryptP      = 16
+	maxScryptMemory = 256 << 20 // scrypt需要约128*N*r字节内存
+)
+
+var (
+	// ErrNotFound 指定名称的身份不存在
+	ErrNotFound = errors.New("keystore: 身份不存在")
+	// ErrExists 指定名称的身份已存在
+	ErrExists = errors.New("keystore: 身份已存在")
+	// ErrWrongPassphrase 口令错误或数据已损坏
+	ErrWrongPassphrase = errors.New("keystore: 口令错误或数据已损坏")
+)
+
+// KeyType 密钥类型
+type KeyType string
+
+const (
+	KeyTypeEd25519    KeyType = "ed25519"
+	KeyTypeSecp256k1  KeyType = "secp256k1"
+	KeyTypePrime256v1 KeyType = "prime256v1"
+)
+
+// Entry 身份的公开信息，不包含私钥
+type Entry struct {
+	Name      string    `json:"name"`
+	Principal string    `json:"principal"`
+	KeyType   KeyType   `json:"key_type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// kdfParams scrypt参数
+type kdfParams struct {
+	Name string `json:"name"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+}
+
+// record 文件中保存的一个加密身份
+type record struct {
+	Entry
+	KDF        kdfParams `json:"kdf"`
+	Cipher     string    `json:"cipher"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// file 密钥库文件结构
+type file struct {
+	Version    int       `json:"version"`
+	Identities []*record `json:"identities"`
+}
+
+// Store 保存多个命名身份的加密密钥库，可并发使用
+type Store struct {
+	path string
+
+	mu   sync.Mutex
+	data file
+}
+
+// Open 打开path处的密钥库，文件不存在时创建一个空的密钥库（首次写入时保存）
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, data: file{Version: fileVersion}}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥库失败: %w", err)
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("解析密钥库失败: %w", err)
+	}
+	if s.data.Version != fileVersion {
+		return nil, fmt.Errorf("不支持的密钥库版本: %d", s.data.Version)
+	}
+	if slices.Contains(s.data.Identities, nil) {
+		return nil, errors.New("解析密钥库失败: 身份列表中包含空记录")
+	}
+	return s, nil
+}
+
+// List 返回所有身份的公开信息，按名称排序
+func (s *Store) List() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]Entry, 0, len(s.data.Identities))
+	for _, r := range s.data.Identities {
+		entries = append(entries, r.Entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return entries
+}
+
+// Generate 生成一个新的Ed25519身份并以name保存
+func (s *Store) Generate(name, passphrase string) (*odin_api.KeyIdentity, error) {
+	id, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Import(name, id, passphrase); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// Import 加密保存一个身份，name已存在时返回ErrExists
+func (s *Store) Import(name string, id *odin_api.KeyIdentity, passphrase string) error {
+	pem, err := id.ToPEM()
+	if err != nil {
+		return fmt.Errorf("导出私钥失败: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(name) != nil {
+		return fmt.Errorf("%w: %s", ErrExists, name)
+	}
+
+	r := &record{
+		Entry: Entry{
+			Name:      name,
+			Principal: id.PrincipalText(),
+			KeyType:   keyTypeOf(id),
+			CreatedAt: time.Now().UTC(),
+		},
+	}
+	if err := r.seal(pem, passphrase); err != nil {
+		return err
+	}
+
+	return s.save(append(slices.Clip(s.data.Identities), r))
+}
+
+// ImportPEM 从PEM数据（例如dfx导出的identity.pem）导入身份
+func (s *Store) ImportPEM(name string, pem []byte, passphrase string) (*odin_api.KeyIdentity, error) {
+	id, err := odin_api.LoadPEMIdentity(pem)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Import(name, id, passphrase); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// Get 解密并返回指定身份
+// 返回值实现odin_api.Identity，并可通过AgentIdentity获得agent-go的identity.Identity
+func (s *Store) Get(name, passphrase string) (*odin_api.KeyIdentity, error) {
+	pem, err := s.Export(name, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return odin_api.LoadPEMIdentity(pem)
+}
+
+// Export 解密并返回指定身份的PEM私钥
+func (s *Store) Export(name, passphrase string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.find(name)
+	if r == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return r.open(passphrase)
+}
+
+// Delete 删除指定身份
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.data.Identities, func(r *record) bool { return r.Name == name })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return s.save(slices.Delete(slices.Clone(s.data.Identities), i, i+1))
+}
+
+// Rotate 使用新口令重新加密指定身份，name为空时重新加密全部身份
+// 所有身份都必须能用旧口令解密，否则不做任何修改
+func (s *Store) Rotate(name, oldPassphrase, newPassphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name != "" && s.find(name) == nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	identities := slices.Clone(s.data.Identities)
+	for i, r := range identities {
+		if name != "" && r.Name != name {
+			continue
+		}
+		pem, err := r.open(oldPassphrase)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		rotated := &record{Entry: r.Entry}
+		if err := rotated.seal(pem, newPassphrase); err != nil {
+			return err
+		}
+		identities[i] = rotated
+	}
+	return s.save(identities)
+}
+
+// find 按名称查找身份，调用方需持有锁
+func (s *Store) find(name string) *record {
+	for _, r := range s.data.Identities {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// save 原子地写入包含identities的密钥库文件，写入成功后才替换内存中的身份列表
+// 调用方需持有锁，且不能修改s.data中已有的record
+func (s *Store) save(identities []*record) error {
+	data := file{Version: s.data.Version, Identities: identities}
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("编码密钥库失败: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("创建密钥库目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".keystore-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("设置文件权限失败: %w", err)
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入密钥库失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入密钥库失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("保存密钥库失败: %w", err)
+	}
+	s.data = data
+	return nil
+}
+
+// seal 使用口令加密PEM私钥
+func (r *record) seal(pem []byte, passphrase string) error {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("生成盐值失败: %w", err)
+	}
+	r.KDF = kdfParams{Name: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: salt}
+
+	aead, err := r.KDF.aead(passphrase)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("生成随机数失败: %w", err)
+	}
+
+	r.Cipher = "aes-256-gcm"
+	r.Nonce = nonce
+	r.Ciphertext = aead.Seal(nil, nonce, pem, []byte(r.Name))
+	return nil
+}
+
+// open 使用口令解密PEM私钥
+func (r *record) open(passphrase string) ([]byte, error) {
+	if r.KDF.Name != "scrypt" || r.Cipher != "aes-256-gcm" {
+		return nil, fmt.Errorf("不支持的加密方式: %s/%s", r.KDF.Name, r.Cipher)
+	}
+	aead, err := r.KDF.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	// 长度不符的随机数会使GCM的Open直接panic
+	if len(r.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("无效的随机数长度: %d", len(r.Nonce))
+	}
+	pem, err := aead.Open(nil, r.Nonce, r.Ciphertext, []byte(r.Name))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return pem, nil
+}
+
+// validate 检查scrypt参数是否在合理范围内
+func (p kdfParams) validate() error {
+	if p.N < 2 || p.N > maxScryptN || p.N&(p.N-1) != 0 {
+		return fmt.Errorf("无效的scrypt参数N: %d", p.N)
+	}
+	if p.R < 1 || p.P < 1 || p.P > maxScryptP || p.R*p.P >= 1<<30 {
+		return fmt.Errorf("无效的scrypt参数r=%d, p=%d", p.R, p.P)
+	}
+	if 128*p.N*p.R > maxScryptMemory {
+		return fmt.Errorf("scrypt参数所需内存过大: N=%d, r=%d", p.N, p.R)
+	}
+	return nil
+}
+
+// aead 从口令派生AES-256-GCM密钥
+func (p kdfParams) aead(passphrase string) (cipher.AEAD, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key([]byte(passphrase), p.Salt, p.N, p.R, p.P, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("派生密钥失败: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建加密器失败: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// keyTypeOf 返回身份的密钥类型
+func keyTypeOf(id *odin_api.KeyIdentity) KeyType {
+	switch id.AgentIdentity().(type) {
+	case *identity.Ed25519Identity:
+		return KeyTypeEd25519
+	case *identity.Secp256k1Identity:
+		return KeyTypeSecp256k1
+	case *identity.Prime256v1Identity:
+		return KeyTypePrime256v1
+	}
+	return ""
+}
@@ -0,0 +1,268 @@
+package keystore_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MrHat365/odin-go/keystore"
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+func TestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "keystore.json")
+	store, err := keystore.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	generated, err := store.Generate("main", "correct horse")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	imported, err := odin_api.NewRandomSecp256k1Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Import("alt", imported, "battery staple"); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if err := store.Import("main", imported, "x"); !errors.Is(err, keystore.ErrExists) {
+		t.Errorf("Import duplicate = %v, want ErrExists", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("keystore mode = %o, want 600", mode)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "PRIVATE KEY") {
+		t.Error("keystore file contains an unencrypted private key")
+	}
+
+	reopened, err := keystore.Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	entries := reopened.List()
+	if len(entries) != 2 || entries[0].Name != "alt" || entries[1].Name != "main" {
+		t.Fatalf("List = %+v, want alt and main", entries)
+	}
+	if entries[0].KeyType != keystore.KeyTypeSecp256k1 || entries[1].KeyType != keystore.KeyTypeEd25519 {
+		t.Errorf("key types = %s, %s", entries[0].KeyType, entries[1].KeyType)
+	}
+	if entries[1].Principal != generated.PrincipalText() {
+		t.Errorf("Principal = %s, want %s", entries[1].Principal, generated.PrincipalText())
+	}
+
+	got, err := reopened.Get("main", "correct horse")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.PrincipalText() != generated.PrincipalText() {
+		t.Errorf("decrypted principal = %s, want %s", got.PrincipalText(), generated.PrincipalText())
+	}
+	if got, err = reopened.Get("alt", "battery staple"); err != nil || got.PrincipalText() != imported.PrincipalText() {
+		t.Errorf("Get alt = %v, %v, want %s", got, err, imported.PrincipalText())
+	}
+}
+
+func TestWrongPassphrase(t *testing.T) {
+	store, err := keystore.Open(filepath.Join(t.TempDir(), "keystore.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Generate("main", "right"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get("main", "wrong"); !errors.Is(err, keystore.ErrWrongPassphrase) {
+		t.Errorf("Get = %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := store.Export("main", "wrong"); !errors.Is(err, keystore.ErrWrongPassphrase) {
+		t.Errorf("Export = %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := store.Get("missing", "right"); !errors.Is(err, keystore.ErrNotFound) {
+		t.Errorf("Get missing = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRotateAndDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	store, err := keystore.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := store.Generate("main", "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Rotate("main", "wrong", "new"); !errors.Is(err, keystore.ErrWrongPassphrase) {
+		t.Errorf("Rotate with wrong passphrase = %v, want ErrWrongPassphrase", err)
+	}
+	if err := store.Rotate("main", "old", "new"); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if _, err := store.Get("main", "old"); !errors.Is(err, keystore.ErrWrongPassphrase) {
+		t.Errorf("Get with old passphrase = %v, want ErrWrongPassphrase", err)
+	}
+	got, err := store.Get("main", "new")
+	if err != nil || got.PrincipalText() != id.PrincipalText() {
+		t.Fatalf("Get with new passphrase = %v, %v", got, err)
+	}
+
+	if err := store.Delete("main"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete("main"); !errors.Is(err, keystore.ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+	reopened, err := keystore.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := reopened.List(); len(entries) != 0 {
+		t.Errorf("List after Delete = %+v, want empty", entries)
+	}
+}
+
+func TestFailedSaveKeepsMemoryUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	store, err := keystore.Open(filepath.Join(dir, "keys", "keystore.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 打开之后在目录的位置放一个普通文件，保存一定失败
+	if err := os.WriteFile(filepath.Join(dir, "keys"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Generate("main", "pass"); err == nil {
+		t.Fatal("Generate succeeded, want save error")
+	}
+	if entries := store.List(); len(entries) != 0 {
+		t.Errorf("List after failed save = %+v, want empty", entries)
+	}
+	if _, err := store.Get("main", "pass"); !errors.Is(err, keystore.ErrNotFound) {
+		t.Errorf("Get after failed save = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRejectsUnsafeScryptParameters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	store, err := keystore.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Generate("main", "pass"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		kdf  map[string]int
+	}{
+		{"huge N", map[string]int{"n": 1 << 30, "r": 8, "p": 1}},
+		{"N not power of two", map[string]int{"n": 1000, "r": 8, "p": 1}},
+		{"too much memory", map[string]int{"n": 1 << 20, "r": 8, "p": 1}},
+		{"zero r", map[string]int{"n": 1 << 10, "r": 0, "p": 1}},
+		{"huge p", map[string]int{"n": 1 << 10, "r": 1, "p": 1 << 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := tamper(t, path, func(data map[string]any) {
+				kdf := data["identities"].([]any)[0].(map[string]any)["kdf"].(map[string]any)
+				for k, v := range tt.kdf {
+					kdf[k] = v
+				}
+			})
+
+			s, err := keystore.Open(tampered)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = s.Get("main", "pass")
+			if err == nil || errors.Is(err, keystore.ErrWrongPassphrase) {
+				t.Errorf("Get = %v, want parameter error", err)
+			}
+		})
+	}
+}
+
+func TestRejectsBadNonce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	store, err := keystore.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Generate("main", "pass"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, nonce := range []any{nil, "AAAA", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"} {
+		tampered := tamper(t, path, func(data map[string]any) {
+			data["identities"].([]any)[0].(map[string]any)["nonce"] = nonce
+		})
+		s, err := keystore.Open(tampered)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 长度不符的随机数必须返回错误而不是panic
+		_, err = s.Get("main", "pass")
+		if err == nil || errors.Is(err, keystore.ErrWrongPassphrase) {
+			t.Errorf("Get with nonce %v = %v, want nonce error", nonce, err)
+		}
+	}
+}
+
+func TestRejectsNullIdentity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	store, err := keystore.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Generate("main", "pass"); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := tamper(t, path, func(data map[string]any) {
+		data["identities"] = append(data["identities"].([]any), nil)
+	})
+	if _, err := keystore.Open(tampered); err == nil {
+		t.Error("Open with null identity succeeded, want error")
+	}
+}
+
+// tamper 将path处的密钥库按edit修改后写入新的临时文件，返回新文件路径
+func tamper(t *testing.T, path string, edit func(data map[string]any)) string {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatal(err)
+	}
+	edit(data)
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := filepath.Join(t.TempDir(), "keystore.json")
+	if err := os.WriteFile(tampered, out, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return tampered
+}
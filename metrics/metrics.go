@@ -0,0 +1,57 @@
+// Package metrics 定义odin_api和agent_sdk上报指标的接口，并提供Prometheus文本格式的导出器
+//
+// SDK只依赖Recorder接口，未设置时不会产生任何开销。Registry是一个不依赖第三方库的内存实现，
+// 可以直接挂载为http.Handler供Prometheus抓取；已经使用Prometheus客户端库的项目
+// 也可以自行实现Recorder进行桥接。
+package metrics
+
+// SDK上报的指标名称
+const (
+	// RequestsTotal odin_api的HTTP请求次数（每次重试单独计数），标签：endpoint、method、status
+	RequestsTotal = "odin_api_requests_total"
+	// RequestDuration odin_api单次HTTP请求的耗时（秒），标签：endpoint、method
+	RequestDuration = "odin_api_request_duration_seconds"
+	// RetriesTotal odin_api的重试次数，标签：endpoint、method
+	RetriesTotal = "odin_api_retries_total"
+	// RateLimitWait odin_api等待客户端限流器的时间（秒），标签：group
+	RateLimitWait = "odin_api_rate_limit_wait_seconds"
+	// DecodeErrorsTotal odin_api响应解析失败的次数，标签：endpoint
+	DecodeErrorsTotal = "odin_api_decode_errors_total"
+	// SchemaDriftTotal 严格解析模式下发现响应结构差异的次数，标签：endpoint
+	SchemaDriftTotal = "odin_api_schema_drift_total"
+	// CanisterCallsTotal agent_sdk的Canister调用次数，标签：method、kind（query或update）、result（ok或error）
+	CanisterCallsTotal = "agent_sdk_calls_total"
+	// CanisterCallDuration agent_sdk的Canister调用耗时（秒），标签：method、kind
+	CanisterCallDuration = "agent_sdk_call_duration_seconds"
+)
+
+// Label 指标的标签
+type Label struct {
+	Name  string
+	Value string
+}
+
+// L 创建标签
+func L(name, value string) Label {
+	return Label{Name: name, Value: value}
+}
+
+// Recorder 接收SDK上报的指标，实现必须可以并发调用
+type Recorder interface {
+	// Add 将计数器增加value
+	Add(name string, value float64, labels ...Label)
+	// Observe 向直方图记录一次观测值
+	Observe(name string, value float64, labels ...Label)
+}
+
+// help SDK指标的说明
+var help = map[string]string{
+	RequestsTotal:        "odin_api HTTP请求次数，每次重试单独计数",
+	RequestDuration:      "odin_api单次HTTP请求的耗时（秒）",
+	RetriesTotal:         "odin_api的重试次数",
+	RateLimitWait:        "odin_api等待客户端限流器的时间（秒）",
+	DecodeErrorsTotal:    "odin_api响应解析失败的次数",
+	SchemaDriftTotal:     "严格解析模式下发现响应结构差异的次数",
+	CanisterCallsTotal:   "agent_sdk的Canister调用次数",
+	CanisterCallDuration: "agent_sdk的Canister调用耗时（秒）",
+}
@@ -0,0 +1,262 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets 直方图默认的桶上限（秒），与Prometheus客户端库的默认值相同
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// kind 指标类型
+type kind int
+
+const (
+	counterKind kind = iota + 1
+	histogramKind
+)
+
+// Registry 内存中的Recorder实现，以Prometheus文本格式导出指标
+// Registry实现了http.Handler，可以直接挂载到 /metrics
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+	help     map[string]string
+	buckets  map[string][]float64
+}
+
+// family 同名指标的所有时间序列
+type family struct {
+	kind    kind
+	buckets []float64
+	series  map[string]*series
+}
+
+// series 一组标签对应的时间序列
+type series struct {
+	labels []Label
+	value  float64  // 计数器的值
+	counts []uint64 // 直方图每个桶的计数（非累计），最后一个为+Inf
+	sum    float64
+	count  uint64
+}
+
+// NewRegistry 创建空的Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+		help:     make(map[string]string),
+		buckets:  make(map[string][]float64),
+	}
+}
+
+// Describe 设置指标的说明，SDK指标已有默认说明
+func (r *Registry) Describe(name, help string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.help[name] = help
+}
+
+// SetBuckets 设置直方图的桶上限，需要在第一次Observe之前调用
+func (r *Registry) SetBuckets(name string, buckets []float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := slices.Clone(buckets)
+	slices.Sort(b)
+	r.buckets[name] = b
+}
+
+// Add 实现Recorder，名称已被用作直方图时忽略
+func (r *Registry) Add(name string, value float64, labels ...Label) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.series(name, counterKind, labels)
+	if s != nil {
+		s.value += value
+	}
+}
+
+// Observe 实现Recorder，名称已被用作计数器时忽略
+func (r *Registry) Observe(name string, value float64, labels ...Label) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := r.family(name, histogramKind)
+	if f == nil {
+		return
+	}
+	s := r.series(name, histogramKind, labels)
+	i, _ := slices.BinarySearch(f.buckets, value)
+	s.counts[i]++
+	s.sum += value
+	s.count++
+}
+
+// family 返回指定名称的指标族，不存在时创建，类型不一致时返回nil，调用方需持有锁
+func (r *Registry) family(name string, k kind) *family {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{kind: k, series: make(map[string]*series)}
+		if k == histogramKind {
+			f.buckets = r.buckets[name]
+			if f.buckets == nil {
+				f.buckets = DefaultBuckets
+			}
+		}
+		r.families[name] = f
+	}
+	if f.kind != k {
+		return nil
+	}
+	return f
+}
+
+// series 返回指定标签的时间序列，不存在时创建，调用方需持有锁
+func (r *Registry) series(name string, k kind, labels []Label) *series {
+	f := r.family(name, k)
+	if f == nil {
+		return nil
+	}
+
+	sorted := slices.Clone(labels)
+	slices.SortFunc(sorted, func(a, b Label) int { return strings.Compare(a.Name, b.Name) })
+	key := formatLabels(sorted)
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: sorted}
+		if k == histogramKind {
+			s.counts = make([]uint64, len(f.buckets)+1)
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// ServeHTTP 以Prometheus文本格式输出所有指标
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteText(w)
+}
+
+// WriteText 以Prometheus文本格式写入所有指标，指标和时间序列按名称排序
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		f := r.families[name]
+		if text := r.helpFor(name); text != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", name, escapeHelp(text))
+		}
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		switch f.kind {
+		case counterKind:
+			fmt.Fprintf(bw, "# TYPE %s counter\n", name)
+			for _, key := range keys {
+				fmt.Fprintf(bw, "%s%s %s\n", name, key, formatFloat(f.series[key].value))
+			}
+		case histogramKind:
+			fmt.Fprintf(bw, "# TYPE %s histogram\n", name)
+			for _, key := range keys {
+				writeHistogram(bw, name, f.buckets, f.series[key])
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// helpFor 返回指标的说明，调用方需持有锁
+func (r *Registry) helpFor(name string) string {
+	if text, ok := r.help[name]; ok {
+		return text
+	}
+	return help[name]
+}
+
+// writeHistogram 写入直方图的累计桶、总和与计数
+func writeHistogram(w io.Writer, name string, buckets []float64, s *series) {
+	var cumulative uint64
+	for i, upper := range buckets {
+		cumulative += s.counts[i]
+		le := append(slices.Clone(s.labels), L("le", formatFloat(upper)))
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(le), cumulative)
+	}
+	le := append(slices.Clone(s.labels), L("le", "+Inf"))
+	fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(le), s.count)
+
+	labels := formatLabels(s.labels)
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, formatFloat(s.sum))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, s.count)
+}
+
+// formatLabels 格式化为 {a="1",b="2"}，没有标签时返回空字符串
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(label.Name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(label.Value))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// formatFloat 按Prometheus文本格式格式化数值
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// escapeLabel 转义标签值中的反斜杠、双引号和换行
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// escapeHelp 转义说明中的反斜杠和换行
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
@@ -0,0 +1,66 @@
+package metrics_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MrHat365/odin-go/metrics"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.SetBuckets("latency_seconds", []float64{0.1, 1})
+	r.Describe("jobs_total", "处理的任务数\n包括失败")
+
+	r.Add("jobs_total", 1, metrics.L("queue", "b"))
+	r.Add("jobs_total", 2, metrics.L("queue", "a"), metrics.L("kind", `say "hi"`))
+	r.Add("jobs_total", 1.5, metrics.L("kind", `say "hi"`), metrics.L("queue", "a"))
+	r.Observe("latency_seconds", 0.05)
+	r.Observe("latency_seconds", 0.5)
+	r.Observe("latency_seconds", 5)
+	// 类型不一致的上报被忽略
+	r.Observe("jobs_total", 1)
+	r.Add("latency_seconds", 1)
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP jobs_total 处理的任务数\n包括失败
+# TYPE jobs_total counter
+jobs_total{kind="say \"hi\"",queue="a"} 3.5
+jobs_total{queue="b"} 1
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 5.55
+latency_seconds_count 3
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteText =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRegistryDefaultHelpAndHandler(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.Add(metrics.RetriesTotal, 1, metrics.L("endpoint", "/token/{id}"), metrics.L("method", "GET"))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %s, want Prometheus text format", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# HELP odin_api_retries_total odin_api的重试次数\n",
+		"# TYPE odin_api_retries_total counter\n",
+		`odin_api_retries_total{endpoint="/token/{id}",method="GET"} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
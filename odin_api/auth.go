@@ -0,0 +1,118 @@
+package odin_api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultReferrer AuthIdentity注册新用户时使用的推荐码
+const DefaultReferrer = "zg8khi8rz0"
+
+// AuthOptions Authenticate的可选参数
+type AuthOptions struct {
+	// Referrer 注册新用户时使用的推荐码，为空时请求中不包含该字段
+	Referrer string
+	// Clock 生成签名时间戳的时间来源，默认time.Now
+	// 客户端检测到的服务器时钟偏差会叠加在该时间之上
+	Clock func() time.Time
+}
+
+// Authenticate 使用客户端配置的传输层进行身份验证和身份注册，返回授权令牌
+// 若服务器因时间戳偏差拒绝请求，会根据响应的Date头校准时钟后重试一次
+func (c *Client) Authenticate(ctx context.Context, identity Identity, opts AuthOptions) (string, error) {
+	token, err := c.authenticate(ctx, identity, opts)
+	if err == nil {
+		return token, nil
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !isTimestampRejection(apiErr) {
+		return "", err
+	}
+	serverTime, ok := serverDate(apiErr.Header)
+	if !ok {
+		return "", err
+	}
+	// 偏差必须相对于签名时使用的时钟计算，否则自定义Clock时校准会失效
+	c.clockOffset.Store(int64(serverTime.Sub(opts.clock()())))
+
+	return c.authenticate(ctx, identity, opts)
+}
+
+// ClockOffset 返回校准后的服务器时钟相对于本地时钟的偏差
+func (c *Client) ClockOffset() time.Duration {
+	return time.Duration(c.clockOffset.Load())
+}
+
+// authenticate 签名当前时间戳并发送一次认证请求
+func (c *Client) authenticate(ctx context.Context, identity Identity, opts AuthOptions) (string, error) {
+	// 获取当前时间戳
+	now := opts.clock()().Add(c.ClockOffset()).UnixMilli()
+	timestamp := strconv.FormatInt(now, 10)
+
+	// 签名时间戳
+	signature, err := identity.Sign([]byte(timestamp))
+	if err != nil {
+		return "", fmt.Errorf("签名生成失败: %w", err)
+	}
+
+	// 创建授权请求
+	authReq := AuthRequest{
+		PublicKey: base64.StdEncoding.EncodeToString(identity.GetPublicKey()),
+		Timestamp: timestamp,
+		Signature: base64.StdEncoding.EncodeToString(signature),
+		Referrer:  opts.Referrer,
+	}
+
+	// 发送请求
+	resp, err := c.PostCtx(ctx, "/auth", authReq)
+	if err != nil {
+		return "", err
+	}
+
+	// 解析响应
+	var authToken struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(resp, &authToken); err != nil {
+		return "", fmt.Errorf("解析授权令牌失败: %w", err)
+	}
+
+	return authToken.Token, nil
+}
+
+// clock 返回生成签名时间戳的时间来源
+func (o AuthOptions) clock() func() time.Time {
+	if o.Clock == nil {
+		return time.Now
+	}
+	return o.Clock
+}
+
+// isTimestampRejection 判断认证失败是否由时间戳过期或超前引起
+func isTimestampRejection(err *APIError) bool {
+	if err.StatusCode != http.StatusBadRequest && err.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	msg := strings.ToLower(err.ServerMessage)
+	return strings.Contains(msg, "timestamp") || strings.Contains(msg, "clock")
+}
+
+// serverDate 从响应头中读取服务器时间
+func serverDate(header http.Header) (time.Time, bool) {
+	if header == nil {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
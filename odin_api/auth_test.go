@@ -0,0 +1,92 @@
+package odin_api_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+// skewServer 启动时钟比本地快skew的模拟服务器
+func skewServer(t *testing.T, skew time.Duration) *odintest.Server {
+	t.Helper()
+	srv := odintest.NewServer(odintest.Config{
+		MaxClockSkew: 10 * time.Second,
+		Now:          func() time.Time { return time.Now().Add(skew) },
+	})
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// assertOffset 检查校准后的偏差，Date头只精确到秒
+func assertOffset(t *testing.T, client *odin_api.Client, want time.Duration) {
+	t.Helper()
+	if got := client.ClockOffset(); got < want-2*time.Second || got > want+2*time.Second {
+		t.Errorf("ClockOffset = %v, want about %v", got, want)
+	}
+}
+
+func TestAuthenticateResyncsClock(t *testing.T) {
+	srv := skewServer(t, time.Hour)
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := srv.NewClient()
+	token, err := client.Authenticate(context.Background(), identity, odin_api.AuthOptions{})
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if token == "" {
+		t.Error("token is empty")
+	}
+	assertOffset(t, client, time.Hour)
+}
+
+func TestAuthenticateResyncsCustomClock(t *testing.T) {
+	// 偏差相对于签名使用的Clock计算，而不是本地时钟
+	srv := skewServer(t, 0)
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := srv.NewClient()
+	opts := odin_api.AuthOptions{Clock: func() time.Time { return time.Now().Add(-2 * time.Hour) }}
+	if _, err := client.Authenticate(context.Background(), identity, opts); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	assertOffset(t, client, 2*time.Hour)
+
+	// 校准后的偏差在后续认证中继续生效
+	if _, err := client.Authenticate(context.Background(), identity, opts); err != nil {
+		t.Fatalf("second Authenticate: %v", err)
+	}
+	if got := srv.Requests(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestAuthenticateNoResyncOnOtherErrors(t *testing.T) {
+	srv := skewServer(t, 0)
+	srv.AddFault(odintest.Fault{PathPrefix: "/auth", Status: 503})
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := srv.NewClient()
+	if _, err := client.Authenticate(context.Background(), identity, odin_api.AuthOptions{}); !errors.Is(err, odin_api.ErrServer) {
+		t.Fatalf("err = %v, want ErrServer", err)
+	}
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	if got := client.ClockOffset(); got != 0 {
+		t.Errorf("ClockOffset = %v, want 0", got)
+	}
+}
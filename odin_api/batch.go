@@ -0,0 +1,157 @@
+package odin_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultBatchConcurrency 批量请求默认的最大并发数
+const DefaultBatchConcurrency = 8
+
+// defaultBatchPause 服务器返回429但未提供Retry-After时，批量请求整体暂停的时间
+const defaultBatchPause = time.Second
+
+// BatchResult 批量请求中单个ID的结果
+type BatchResult[T any] struct {
+	ID    string
+	Value *T
+	Err   error
+}
+
+// WithBatchConcurrency 设置GetTokensMany等批量请求的最大并发数，默认DefaultBatchConcurrency
+//
+// 批量请求同样经过客户端的限流器，并发数只决定同时进行中的请求数量，
+// 实际的请求速率仍由WithRateLimiter和WithEndpointRateLimiter控制。
+func WithBatchConcurrency(n int) Option {
+	return func(c *Client) {
+		c.batchConcurrency = n
+	}
+}
+
+// GetTokensMany 并发获取多个代币的详情，结果与ids的顺序一致
+// 单个代币失败不会影响其他代币，错误记录在对应的BatchResult中
+func (c *Client) GetTokensMany(ids []string) []BatchResult[TokenDetail] {
+	return c.GetTokensManyCtx(context.Background(), ids)
+}
+
+// GetTokensManyCtx 与GetTokensMany相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetTokensManyCtx(ctx context.Context, ids []string) []BatchResult[TokenDetail] {
+	return batch(ctx, c, ids, c.GetOdinFunTokenCtx)
+}
+
+// GetUsersMany 并发获取多个用户的信息，结果与principalIDs的顺序一致
+// 单个用户失败不会影响其他用户，错误记录在对应的BatchResult中
+func (c *Client) GetUsersMany(principalIDs []string) []BatchResult[OdinUser] {
+	return c.GetUsersManyCtx(context.Background(), principalIDs)
+}
+
+// GetUsersManyCtx 与GetUsersMany相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetUsersManyCtx(ctx context.Context, principalIDs []string) []BatchResult[OdinUser] {
+	return batch(ctx, c, principalIDs, c.GetOdinFunUserCtx)
+}
+
+// GetHoldersMany 并发获取多个代币的持有者（第一页），结果与ids的顺序一致
+// 单个代币失败不会影响其他代币，错误记录在对应的BatchResult中
+func (c *Client) GetHoldersMany(ids []string) []BatchResult[Holders] {
+	return c.GetHoldersManyCtx(context.Background(), ids)
+}
+
+// GetHoldersManyCtx 与GetHoldersMany相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetHoldersManyCtx(ctx context.Context, ids []string) []BatchResult[Holders] {
+	return batch(ctx, c, ids, c.GetHoldersCtx)
+}
+
+// batch 以有限的并发数对每个ID执行fetch，结果与ids的顺序一致
+// 任一请求被服务器限流（429）时，同一批次的所有请求暂停到Retry-After之后再继续
+func batch[T any](ctx context.Context, c *Client, ids []string, fetch func(ctx context.Context, id string) (*T, error)) []BatchResult[T] {
+	results := make([]BatchResult[T], len(ids))
+	if len(ids) == 0 {
+		return results
+	}
+
+	workers := c.batchConcurrency
+	if workers <= 0 {
+		workers = DefaultBatchConcurrency
+	}
+	workers = min(workers, len(ids))
+
+	ctx = context.WithValue(ctx, batchKey, new(batchPause))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].Value, results[i].Err = fetch(ctx, ids[i])
+			}
+		}()
+	}
+	for i, id := range ids {
+		results[i].ID = id
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// batchPause 同一批次请求共享的暂停状态
+type batchPause struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// wait 等待到暂停结束，ctx被取消或超时时返回ctx.Err()
+func (p *batchPause) wait(ctx context.Context) error {
+	p.mu.Lock()
+	d := time.Until(p.until)
+	p.mu.Unlock()
+
+	return sleepContext(ctx, d)
+}
+
+// observe 请求被服务器限流时延长暂停时间
+func (p *batchPause) observe(err error) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	d := apiErr.RetryAfter
+	if d <= 0 {
+		d = defaultBatchPause
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(p.until) {
+		p.until = until
+	}
+}
+
+// batchMiddleware 批量请求的每次尝试在发送前等待批次的暂停结束，并在被限流时暂停整个批次
+// 不是由批量请求发出的请求直接放行
+func batchMiddleware(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		pause, ok := req.Context().Value(batchKey).(*batchPause)
+		if !ok {
+			return next.Do(req)
+		}
+		if err := pause.wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("等待限流失败: %w", err)
+		}
+		resp, err := next.Do(req)
+		pause.observe(err)
+		return resp, err
+	})
+}
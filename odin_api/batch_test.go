@@ -0,0 +1,77 @@
+package odin_api_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+func TestGetTokensManyKeepsOrder(t *testing.T) {
+	srv := newServer(t)
+	var ids []string
+	for i := range 20 {
+		id := fmt.Sprintf("t%02d", i)
+		srv.AddToken(odin_api.TokenDetail{ID: id, Name: "token " + id})
+		ids = append(ids, id)
+	}
+	ids = append(ids, "missing")
+
+	client := srv.NewClient(odin_api.WithBatchConcurrency(4))
+	results := client.GetTokensMany(ids)
+	if len(results) != len(ids) {
+		t.Fatalf("results = %d, want %d", len(results), len(ids))
+	}
+	for i, r := range results[:20] {
+		if r.ID != ids[i] || r.Err != nil || r.Value == nil || r.Value.Name != "token "+ids[i] {
+			t.Errorf("results[%d] = %+v, want token %s", i, r, ids[i])
+		}
+	}
+	if last := results[20]; last.ID != "missing" || !errors.Is(last.Err, odin_api.ErrNotFound) || last.Value != nil {
+		t.Errorf("missing token result = %+v, want ErrNotFound", last)
+	}
+}
+
+func TestBatchPausesOnRateLimit(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{PathPrefix: "/token/", Status: http.StatusTooManyRequests, RetryAfter: time.Second, Times: 1})
+	client := srv.NewClient(
+		odin_api.WithBatchConcurrency(4),
+		odin_api.WithRetryPolicy(odin_api.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}),
+	)
+
+	start := time.Now()
+	results := client.GetTokensMany([]string{"2jjj", "2jjj", "2jjj", "2jjj"})
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("results[%d]: %v", i, r.Err)
+		}
+	}
+	// 429之后所有请求都等待Retry-After，而不是各自在1毫秒后重试
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("elapsed = %v, want the batch to pause for Retry-After", elapsed)
+	}
+	if got := srv.Requests(); got != 5 {
+		t.Errorf("requests = %d, want 5", got)
+	}
+}
+
+func TestBatchCanceled(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{PathPrefix: "/user/", Latency: time.Second})
+	client := srv.NewClient(odin_api.WithBatchConcurrency(2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	results := client.GetUsersManyCtx(ctx, []string{"a", "b", "c", "d"})
+	for i, r := range results {
+		if !errors.Is(r.Err, context.DeadlineExceeded) {
+			t.Errorf("results[%d].Err = %v, want DeadlineExceeded", i, r.Err)
+		}
+	}
+}
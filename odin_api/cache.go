@@ -0,0 +1,300 @@
+package odin_api
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheConfig 响应缓存的配置
+type CacheConfig struct {
+	MaxEntries int           // LRU缓存的最大条目数，默认1024
+	DefaultTTL time.Duration // 未匹配TTLs时的缓存时间，0表示不缓存
+	// TTLs 按端点路径前缀配置缓存时间，使用最长匹配的前缀
+	// 例如 {"/currency/btc": 30 * time.Second, "/token/": 5 * time.Second}
+	TTLs map[string]time.Duration
+	// StaleWhileRevalidate 过期后仍可返回旧数据的时长，期间在后台刷新
+	StaleWhileRevalidate time.Duration
+}
+
+// CacheStats 缓存统计信息
+type CacheStats struct {
+	Hits          uint64 // 命中未过期数据
+	StaleHits     uint64 // 命中过期但可用的数据
+	Misses        uint64 // 未命中，需要请求服务器
+	Revalidations uint64 // 通过If-None-Match确认数据未变化（304）
+	Coalesced     uint64 // 合并到进行中相同请求的次数
+	Entries       int    // 当前条目数
+}
+
+// HitRatio 返回命中率（包括过期命中）
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.StaleHits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.StaleHits) / float64(total)
+}
+
+// cacheEntry 一个缓存条目
+type cacheEntry struct {
+	key      string
+	body     []byte
+	etag     string
+	storedAt time.Time
+	ttl      time.Duration
+}
+
+// flight 进行中的请求，用于合并并发的相同请求
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// Cache 带TTL、过期重新验证和并发请求合并的LRU响应缓存
+// 只缓存GET请求的成功响应，可在多个Client之间共享
+type Cache struct {
+	cfg CacheConfig
+
+	mu      sync.Mutex
+	ll      *list.List
+	items   map[string]*list.Element
+	flights map[string]*flight
+	stats   CacheStats
+}
+
+// NewCache 创建响应缓存
+func NewCache(cfg CacheConfig) *Cache {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 1024
+	}
+	return &Cache{
+		cfg:     cfg,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+		flights: make(map[string]*flight),
+	}
+}
+
+// WithCache 为客户端的GET请求启用响应缓存
+func WithCache(cache *Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// Stats 返回缓存统计信息
+func (cache *Cache) Stats() CacheStats {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	stats := cache.stats
+	stats.Entries = cache.ll.Len()
+	return stats
+}
+
+// Purge 清空所有缓存条目
+func (cache *Cache) Purge() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.ll.Init()
+	cache.items = make(map[string]*list.Element)
+}
+
+// ttlFor 返回端点的缓存时间
+func (cache *Cache) ttlFor(endpoint string) time.Duration {
+	path := endpoint
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	ttl, matched := cache.cfg.DefaultTTL, 0
+	for prefix, d := range cache.cfg.TTLs {
+		if strings.HasPrefix(path, prefix) && len(prefix) > matched {
+			ttl, matched = d, len(prefix)
+		}
+	}
+	return ttl
+}
+
+// lookup 查找条目，返回条目以及其是否新鲜、是否可作为过期数据返回
+func (cache *Cache) lookup(key string, now time.Time) (entry *cacheEntry, fresh, usable bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	el, ok := cache.items[key]
+	if !ok {
+		cache.stats.Misses++
+		return nil, false, false
+	}
+	entry = el.Value.(*cacheEntry)
+	cache.ll.MoveToFront(el)
+
+	age := now.Sub(entry.storedAt)
+	switch {
+	case age < entry.ttl:
+		cache.stats.Hits++
+		return entry, true, true
+	case age < entry.ttl+cache.cfg.StaleWhileRevalidate:
+		cache.stats.StaleHits++
+		return entry, false, true
+	default:
+		cache.stats.Misses++
+		return entry, false, false
+	}
+}
+
+// store 保存条目并按LRU淘汰
+func (cache *Cache) store(entry *cacheEntry) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if el, ok := cache.items[entry.key]; ok {
+		el.Value = entry
+		cache.ll.MoveToFront(el)
+		return
+	}
+	cache.items[entry.key] = cache.ll.PushFront(entry)
+	for cache.ll.Len() > cache.cfg.MaxEntries {
+		oldest := cache.ll.Back()
+		cache.ll.Remove(oldest)
+		delete(cache.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// do 执行fetch，同一key的并发调用只会执行一次
+// fetch在不受调用方取消影响、超时为DefaultTimeout的context中执行，
+// 每个调用方只等待到自己的ctx结束，返回的响应体是各自的副本
+func (cache *Cache) do(ctx context.Context, key string, fetch func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	f := cache.start(ctx, key, fetch)
+	select {
+	case <-f.done:
+		if f.err != nil {
+			return nil, f.err
+		}
+		return bytes.Clone(f.body), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// start 在后台开始执行fetch并返回对应的flight，同一key已有进行中的请求时直接返回该请求
+func (cache *Cache) start(ctx context.Context, key string, fetch func(ctx context.Context) ([]byte, error)) *flight {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if f, ok := cache.flights[key]; ok {
+		cache.stats.Coalesced++
+		return f
+	}
+	f := &flight{done: make(chan struct{})}
+	cache.flights[key] = f
+
+	go func() {
+		bg, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultTimeout)
+		defer cancel()
+		f.body, f.err = fetch(bg)
+
+		cache.mu.Lock()
+		delete(cache.flights, key)
+		cache.mu.Unlock()
+		close(f.done)
+	}()
+	return f
+}
+
+// cacheKey 根据请求URL和当前的授权令牌生成缓存键，令牌只以哈希形式保存
+// 生成缓存键不会触发认证，绑定了Session但尚未获取令牌时按无令牌处理
+func (c *Client) cacheKey(ctx context.Context, endpoint string) (string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	key := req.URL.String()
+	if token := c.currentToken(); token != "" {
+		sum := sha256.Sum256([]byte("Bearer " + token))
+		key += "#" + hex.EncodeToString(sum[:8])
+	}
+	return key, nil
+}
+
+// cachedGet 经由缓存发送GET请求
+func (c *Client) cachedGet(ctx context.Context, endpoint string) ([]byte, error) {
+	cache := c.cache
+	ttl := cache.ttlFor(endpoint)
+	if ttl <= 0 {
+		return c.fetchConditional(ctx, "", endpoint, nil, 0)
+	}
+
+	key, err := c.cacheKey(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, fresh, usable := cache.lookup(key, time.Now())
+	if fresh {
+		return bytes.Clone(entry.body), nil
+	}
+
+	fetch := func(ctx context.Context) ([]byte, error) {
+		return c.fetchConditional(ctx, key, endpoint, entry, ttl)
+	}
+	if usable {
+		// 返回过期数据，同时在后台刷新
+		cache.start(ctx, key, fetch)
+		return bytes.Clone(entry.body), nil
+	}
+
+	return cache.do(ctx, key, fetch)
+}
+
+// fetchConditional 请求服务器并以key更新缓存，ttl不大于0时不缓存
+// 已有条目带ETag时发送If-None-Match，服务器返回304则沿用旧数据
+func (c *Client) fetchConditional(ctx context.Context, key, endpoint string, old *cacheEntry, ttl time.Duration) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if old != nil && old.etag != "" {
+		req.Header.Set("If-None-Match", old.etag)
+	}
+
+	resp, err := c.do(req)
+	if ttl <= 0 {
+		if err != nil {
+			return nil, err
+		}
+		return resp.body, nil
+	}
+
+	var apiErr *APIError
+	if err != nil && old != nil && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotModified {
+		c.cache.mu.Lock()
+		c.cache.stats.Revalidations++
+		c.cache.mu.Unlock()
+
+		c.cache.store(&cacheEntry{key: key, body: old.body, etag: old.etag, storedAt: time.Now(), ttl: ttl})
+		return old.body, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.store(&cacheEntry{
+		key:      key,
+		body:     resp.body,
+		etag:     resp.header.Get("ETag"),
+		storedAt: time.Now(),
+		ttl:      ttl,
+	})
+	return resp.body, nil
+}
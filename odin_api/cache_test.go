@@ -0,0 +1,197 @@
+package odin_api_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+// newCachedClient 创建使用cfg响应缓存的客户端
+func newCachedClient(srv *odintest.Server, cfg odin_api.CacheConfig) (*odin_api.Client, *odin_api.Cache) {
+	cache := odin_api.NewCache(cfg)
+	return srv.NewClient(odin_api.WithCache(cache)), cache
+}
+
+func TestCacheHit(t *testing.T) {
+	srv := newServer(t)
+	client, cache := newCachedClient(srv, odin_api.CacheConfig{TTLs: map[string]time.Duration{"/token/": time.Minute}})
+
+	for range 3 {
+		token, err := client.GetOdinFunToken("2jjj")
+		if err != nil {
+			t.Fatalf("GetOdinFunToken: %v", err)
+		}
+		if token.Name != "ODIN" {
+			t.Fatalf("Name = %q, want ODIN", token.Name)
+		}
+	}
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("stats = %+v, want 2 hits, 1 miss, 1 entry", stats)
+	}
+
+	// 未配置TTL的端点不缓存
+	for range 2 {
+		if _, err := client.GetBTCPrice(); err != nil {
+			t.Fatalf("GetBTCPrice: %v", err)
+		}
+	}
+	if got := srv.Requests(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	srv := newServer(t)
+	client, _ := newCachedClient(srv, odin_api.CacheConfig{DefaultTTL: time.Minute})
+
+	first, err := client.Get("/token/2jjj")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	want := bytes.Clone(first)
+	clear(first)
+
+	second, err := client.Get("/token/2jjj")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !bytes.Equal(second, want) {
+		t.Errorf("cached body was modified through a returned slice: %q", second)
+	}
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	srv := newServer(t)
+	client, cache := newCachedClient(srv, odin_api.CacheConfig{DefaultTTL: time.Millisecond})
+
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	token, err := client.GetOdinFunToken("2jjj")
+	if err != nil {
+		t.Fatalf("GetOdinFunToken after expiry: %v", err)
+	}
+	if token.Name != "ODIN" {
+		t.Errorf("Name = %q, want ODIN", token.Name)
+	}
+	if stats := cache.Stats(); stats.Revalidations != 1 || stats.Misses != 2 {
+		t.Errorf("stats = %+v, want 1 revalidation after 2 misses", stats)
+	}
+
+	// 数据变化后服务器返回新内容
+	srv.AddToken(odin_api.TokenDetail{ID: "2jjj", Name: "ODIN2"})
+	time.Sleep(5 * time.Millisecond)
+	if token, err = client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken after change: %v", err)
+	}
+	if token.Name != "ODIN2" {
+		t.Errorf("Name = %q, want ODIN2", token.Name)
+	}
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	srv := newServer(t)
+	client, cache := newCachedClient(srv, odin_api.CacheConfig{
+		DefaultTTL:           time.Millisecond,
+		StaleWhileRevalidate: time.Minute,
+	})
+
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+	srv.AddToken(odin_api.TokenDetail{ID: "2jjj", Name: "ODIN2"})
+	time.Sleep(5 * time.Millisecond)
+
+	token, err := client.GetOdinFunToken("2jjj")
+	if err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+	if token.Name != "ODIN" {
+		t.Errorf("stale Name = %q, want ODIN", token.Name)
+	}
+	if stats := cache.Stats(); stats.StaleHits != 1 {
+		t.Errorf("stats = %+v, want 1 stale hit", stats)
+	}
+
+	// 后台刷新完成后返回新数据
+	deadline := time.Now().Add(time.Second)
+	for {
+		token, err = client.GetOdinFunToken("2jjj")
+		if err == nil && token.Name == "ODIN2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("background refresh did not complete: %+v, %v", token, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCacheCoalescesConcurrentRequests(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{PathPrefix: "/token/", Latency: 50 * time.Millisecond, Times: 1})
+	client, cache := newCachedClient(srv, odin_api.CacheConfig{DefaultTTL: time.Minute})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = client.GetOdinFunToken("2jjj")
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("caller %d: %v", i, err)
+		}
+	}
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	if stats := cache.Stats(); stats.Coalesced != 4 {
+		t.Errorf("stats = %+v, want 4 coalesced", stats)
+	}
+}
+
+func TestCacheCoalescedCallersKeepOwnContext(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{PathPrefix: "/token/", Latency: 100 * time.Millisecond, Times: 1})
+	client, _ := newCachedClient(srv, odin_api.CacheConfig{DefaultTTL: time.Minute})
+
+	// 发起请求的调用方超时不影响其他等待同一请求的调用方
+	short, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetOdinFunTokenCtx(short, "2jjj")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("short caller err = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 80*time.Millisecond {
+		t.Errorf("short caller returned after %v, want about 20ms", elapsed)
+	}
+
+	token, err := client.GetOdinFunToken("2jjj")
+	if err != nil {
+		t.Fatalf("second caller: %v", err)
+	}
+	if token.Name != "ODIN" {
+		t.Errorf("Name = %q, want ODIN", token.Name)
+	}
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
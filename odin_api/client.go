@@ -2,14 +2,23 @@ package odin_api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
+	"sync/atomic"
 	"time"
+
+	"github.com/MrHat365/odin-go/metrics"
+	"github.com/MrHat365/odin-go/tracing"
 )
 
+// DefaultTimeout 默认的请求超时时间
+const DefaultTimeout = time.Second * 30
+
 const (
 	// BaseURL Odin.fun API的基础URL
 	BaseURL = "https://api.odin.fun/v1"
@@ -18,16 +27,63 @@ const (
 // Client Odin.fun API客户端结构
 type Client struct {
 	httpClient *http.Client
-	Token      string // 用于授权的令牌
+	baseURL    string
+	userAgent  string
+	headers    http.Header
+	timeout    time.Duration
+	retry      RetryPolicy
+
+	limiter          *RateLimiter
+	groupLimiters    map[EndpointGroup]*RateLimiter
+	batchConcurrency int
+
+	session     *Session
+	cache       *Cache
+	onDrift     func(SchemaDrift)
+	logger      *slog.Logger
+	metrics     metrics.Recorder
+	tracer      tracing.Tracer
+	middleware  []Middleware
+	pipe        Doer          // 组装好的请求管道
+	clockOffset *atomic.Int64 // 服务器时钟偏差（纳秒），在客户端副本之间共享
+
+	Token string // 用于授权的令牌
 }
 
 // NewClient 创建一个新的Odin.fun API客户端
-func NewClient() *Client {
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: time.Second * 30,
-		},
+// 未提供选项时使用BaseURL和30秒超时的http.Client
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL:     BaseURL,
+		headers:     make(http.Header),
+		clockOffset: new(atomic.Int64),
+		logger:      slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	switch {
+	case c.httpClient == nil:
+		timeout := c.timeout
+		if timeout == 0 {
+			timeout = DefaultTimeout
+		}
+		c.httpClient = &http.Client{Timeout: timeout}
+	case c.timeout != 0:
+		// 复制调用方的http.Client，避免修改其超时设置
+		hc := *c.httpClient
+		hc.Timeout = c.timeout
+		c.httpClient = &hc
 	}
+	c.pipe = c.pipeline()
+
+	return c
+}
+
+// BaseURL 返回客户端使用的基础URL
+func (c *Client) BaseURL() string {
+	return c.baseURL
 }
 
 // SetToken 设置授权令牌
@@ -35,74 +91,120 @@ func (c *Client) SetToken(token string) {
 	c.Token = token
 }
 
-// Get 发送GET请求
-func (c *Client) Get(endpoint string) ([]byte, error) {
-	url := fmt.Sprintf("%s%s", BaseURL, endpoint)
-	req, err := http.NewRequest("GET", url, nil)
+// newRequest 创建请求并设置默认请求头和User-Agent，授权由请求管道中的中间件设置
+func (c *Client) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, endpointKey, endpoint), method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
 
-	if c.Token != "" {
-		req.Header.Add("Authorization", "Bearer "+c.Token)
+	for key, values := range c.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("发送请求失败: %w", err)
+	return req, nil
+}
+
+// currentToken 返回当前的授权令牌，不会触发认证
+// 绑定了Session时返回Session已持有的令牌，否则返回Token字段
+func (c *Client) currentToken() string {
+	if c.session != nil {
+		return c.session.current()
 	}
-	defer resp.Body.Close()
+	return c.Token
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("请求失败，状态码: %d", resp.StatusCode)
+// response 读取完毕的成功响应
+type response struct {
+	status int
+	body   []byte
+	header http.Header
+}
+
+// do 通过请求管道发送请求并读取响应体，非2xx状态码以*APIError的形式返回
+func (c *Client) do(req *http.Request) (*response, error) {
+	resp, err := c.pipe.Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("读取响应失败: %w", err)
 	}
+	return &response{status: resp.StatusCode, body: body, header: resp.Header}, nil
+}
+
+// Get 发送GET请求
+func (c *Client) Get(endpoint string) ([]byte, error) {
+	return c.GetCtx(context.Background(), endpoint)
+}
+
+// GetCtx 发送GET请求，ctx被取消或超时时请求会立即中止
+func (c *Client) GetCtx(ctx context.Context, endpoint string) (_ []byte, err error) {
+	ctx, end := c.startSpan(ctx, http.MethodGet, endpoint)
+	defer func() { end(err) }()
+
+	if c.cache != nil {
+		return c.cachedGet(ctx, endpoint)
+	}
+
+	req, err := c.newRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	return body, nil
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
 }
 
 // Post 发送POST请求，带有JSON数据
 func (c *Client) Post(endpoint string, data interface{}) ([]byte, error) {
-	url := fmt.Sprintf("%s%s", BaseURL, endpoint)
+	return c.PostCtx(context.Background(), endpoint, data)
+}
+
+// PostCtx 发送带有JSON数据的POST请求，ctx被取消或超时时请求会立即中止
+func (c *Client) PostCtx(ctx context.Context, endpoint string, data interface{}) (_ []byte, err error) {
+	ctx, end := c.startSpan(ctx, http.MethodPost, endpoint)
+	defer func() { end(err) }()
+
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("JSON编码失败: %w", err)
 	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := c.newRequest(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if c.Token != "" {
-		req.Header.Add("Authorization", "Bearer "+c.Token)
-	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("发送请求失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("请求失败，状态码: %d, 错误: %s", resp.StatusCode, string(body))
-	}
-
-	return body, nil
+	return resp.body, nil
 }
 
 // PostMultipart 发送带有表单数据的POST请求
 func (c *Client) PostMultipart(endpoint string, formData map[string]string) ([]byte, error) {
-	url := fmt.Sprintf("%s%s", BaseURL, endpoint)
+	return c.PostMultipartCtx(context.Background(), endpoint, formData)
+}
+
+// PostMultipartCtx 发送带有表单数据的POST请求，ctx被取消或超时时请求会立即中止
+func (c *Client) PostMultipartCtx(ctx context.Context, endpoint string, formData map[string]string) (_ []byte, err error) {
+	ctx, end := c.startSpan(ctx, http.MethodPost, endpoint)
+	defer func() { end(err) }()
 
 	// 创建表单数据
 	body := &bytes.Buffer{}
@@ -118,30 +220,16 @@ func (c *Client) PostMultipart(endpoint string, formData map[string]string) ([]b
 		return nil, fmt.Errorf("关闭表单写入器失败: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, body)
+	req, err := c.newRequest(ctx, "POST", endpoint, body)
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	if c.Token != "" {
-		req.Header.Add("Authorization", "Bearer "+c.Token)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("发送请求失败: %w", err)
-	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		return nil, err
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("请求失败，状态码: %d, 错误: %s", resp.StatusCode, string(respBody))
-	}
-
-	return respBody, nil
+	return resp.body, nil
 }
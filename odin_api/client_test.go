@@ -0,0 +1,111 @@
+package odin_api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+func TestNewClientDefaults(t *testing.T) {
+	client := odin_api.NewClient()
+	if got := client.BaseURL(); got != odin_api.BaseURL {
+		t.Errorf("BaseURL = %s, want %s", got, odin_api.BaseURL)
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := odin_api.NewClient(
+		odin_api.WithBaseURL(srv.URL+"/v1/"),
+		odin_api.WithUserAgent("odin-bot/1.0"),
+		odin_api.WithHeader("X-Api-Key", "k1"),
+		odin_api.WithHeader("X-Api-Key", "k2"),
+	)
+	client.SetToken("secret")
+
+	if got := client.BaseURL(); got != srv.URL+"/v1" {
+		t.Errorf("BaseURL = %s, want trailing slash trimmed", got)
+	}
+	if _, err := client.Get("/currency/btc"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.URL.Path != "/v1/currency/btc" {
+		t.Errorf("path = %s, want /v1/currency/btc", got.URL.Path)
+	}
+	if ua := got.Header.Get("User-Agent"); ua != "odin-bot/1.0" {
+		t.Errorf("User-Agent = %q, want odin-bot/1.0", ua)
+	}
+	if keys := got.Header.Values("X-Api-Key"); len(keys) != 2 || keys[0] != "k1" || keys[1] != "k2" {
+		t.Errorf("X-Api-Key = %v, want [k1 k2]", keys)
+	}
+	if auth := got.Header.Get("Authorization"); auth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want Bearer secret", auth)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	// 不会修改调用方传入的http.Client
+	hc := &http.Client{}
+	client := odin_api.NewClient(
+		odin_api.WithBaseURL(srv.URL),
+		odin_api.WithHTTPClient(hc),
+		odin_api.WithTimeout(20*time.Millisecond),
+	)
+	start := time.Now()
+	if _, err := client.Get("/slow"); err == nil {
+		t.Fatal("Get succeeded, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Get returned after %v, want about 20ms", elapsed)
+	}
+	if hc.Timeout != 0 {
+		t.Errorf("caller's http.Client timeout = %v, want unchanged", hc.Timeout)
+	}
+}
+
+func TestWithHTTPClientTransport(t *testing.T) {
+	var used bool
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		used = true
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := odin_api.NewClient(
+		odin_api.WithBaseURL(srv.URL),
+		odin_api.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if _, err := client.Get("/ping"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !used {
+		t.Error("custom transport was not used")
+	}
+}
+
+// roundTripFunc 将函数适配为http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
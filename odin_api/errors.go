@@ -0,0 +1,112 @@
+package odin_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 常见API错误的哨兵值，可通过errors.Is判断*APIError的类别
+var (
+	ErrBadRequest   = errors.New("odin_api: 请求参数错误")
+	ErrUnauthorized = errors.New("odin_api: 未授权")
+	ErrForbidden    = errors.New("odin_api: 禁止访问")
+	ErrNotFound     = errors.New("odin_api: 资源不存在")
+	ErrRateLimited  = errors.New("odin_api: 请求过于频繁")
+	ErrServer       = errors.New("odin_api: 服务器错误")
+)
+
+// APIError 表示Odin.fun API返回的非成功响应
+// 调用方可通过errors.As获取状态码、响应体等详细信息
+type APIError struct {
+	StatusCode    int           // HTTP状态码
+	Endpoint      string        // 请求的端点，例如 /token/xxx
+	Method        string        // HTTP方法
+	Body          []byte        // 原始响应体
+	ServerMessage string        // 从响应体中解析出的服务器错误信息
+	RetryAfter    time.Duration // Retry-After响应头指示的等待时间，未提供时为0
+	Header        http.Header   // 响应头
+}
+
+// Error 实现error接口
+func (e *APIError) Error() string {
+	if e.ServerMessage != "" {
+		return fmt.Sprintf("请求失败 %s %s，状态码: %d, 错误: %s", e.Method, e.Endpoint, e.StatusCode, e.ServerMessage)
+	}
+	return fmt.Sprintf("请求失败 %s %s，状态码: %d", e.Method, e.Endpoint, e.StatusCode)
+}
+
+// Is 使errors.Is可以将APIError与对应的哨兵错误匹配
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// newAPIError 根据非成功响应创建APIError
+func newAPIError(method, endpoint string, resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		StatusCode:    resp.StatusCode,
+		Endpoint:      endpoint,
+		Method:        method,
+		Body:          body,
+		ServerMessage: parseServerMessage(body),
+		RetryAfter:    parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Header:        resp.Header,
+	}
+}
+
+// parseServerMessage 从响应体中提取错误信息
+// 优先读取JSON中的message或error字段，否则返回去除空白后的原始文本
+func parseServerMessage(body []byte) string {
+	var payload struct {
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Message != "" {
+			return payload.Message
+		}
+		var errMsg string
+		if json.Unmarshal(payload.Error, &errMsg) == nil && errMsg != "" {
+			return errMsg
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// parseRetryAfter 解析Retry-After响应头，支持秒数和HTTP日期两种格式
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
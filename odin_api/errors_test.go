@@ -0,0 +1,126 @@
+package odin_api_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+// statusServer 启动一个总是返回status和body的服务器
+func statusServer(t *testing.T, status int, header http.Header, body string) *odin_api.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return odin_api.NewClient(odin_api.WithBaseURL(srv.URL))
+}
+
+func TestAPIErrorSentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, odin_api.ErrBadRequest},
+		{http.StatusUnauthorized, odin_api.ErrUnauthorized},
+		{http.StatusForbidden, odin_api.ErrForbidden},
+		{http.StatusNotFound, odin_api.ErrNotFound},
+		{http.StatusTooManyRequests, odin_api.ErrRateLimited},
+		{http.StatusInternalServerError, odin_api.ErrServer},
+		{http.StatusBadGateway, odin_api.ErrServer},
+	}
+	all := []error{
+		odin_api.ErrBadRequest, odin_api.ErrUnauthorized, odin_api.ErrForbidden,
+		odin_api.ErrNotFound, odin_api.ErrRateLimited, odin_api.ErrServer,
+	}
+	for _, tt := range tests {
+		client := statusServer(t, tt.status, nil, "")
+		// 业务方法包装后的错误仍然可以匹配
+		_, err := client.GetOdinFunToken("2jjj")
+		for _, sentinel := range all {
+			if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+				t.Errorf("status %d: errors.Is(err, %v) = %v", tt.status, sentinel, got)
+			}
+		}
+	}
+}
+
+func TestAPIErrorDetails(t *testing.T) {
+	client := statusServer(t, http.StatusNotFound, http.Header{"X-Request-Id": {"abc"}}, `{"message":"token not found"}`)
+
+	_, err := client.GetOdinFunToken("2jjj")
+	var apiErr *odin_api.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Method != http.MethodGet || apiErr.Endpoint != "/token/2jjj" {
+		t.Errorf("APIError = %d %s %s", apiErr.StatusCode, apiErr.Method, apiErr.Endpoint)
+	}
+	if apiErr.ServerMessage != "token not found" || string(apiErr.Body) != `{"message":"token not found"}` {
+		t.Errorf("ServerMessage = %q, Body = %q", apiErr.ServerMessage, apiErr.Body)
+	}
+	if apiErr.Header.Get("X-Request-Id") != "abc" {
+		t.Errorf("Header = %v, want X-Request-Id", apiErr.Header)
+	}
+	if want := "请求失败 GET /token/2jjj，状态码: 404, 错误: token not found"; apiErr.Error() != want {
+		t.Errorf("Error() = %q, want %q", apiErr.Error(), want)
+	}
+}
+
+func TestAPIErrorServerMessage(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"message":"bad"}`, "bad"},
+		{`{"error":"denied"}`, "denied"},
+		{`{"error":{"code":1}}`, `{"error":{"code":1}}`},
+		{"  upstream timeout\n", "upstream timeout"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		client := statusServer(t, http.StatusBadRequest, nil, tt.body)
+		_, err := client.Get("/x")
+		var apiErr *odin_api.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("err = %v, want *APIError", err)
+		}
+		if apiErr.ServerMessage != tt.want {
+			t.Errorf("body %q: ServerMessage = %q, want %q", tt.body, apiErr.ServerMessage, tt.want)
+		}
+	}
+}
+
+func TestAPIErrorRetryAfter(t *testing.T) {
+	date := time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)
+	tests := []struct {
+		header string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"7", 7 * time.Second, 7 * time.Second},
+		{date, 80 * time.Second, 90 * time.Second},
+		{"-1", 0, 0},
+		{"soon", 0, 0},
+	}
+	for _, tt := range tests {
+		client := statusServer(t, http.StatusTooManyRequests, http.Header{"Retry-After": {tt.header}}, "")
+		_, err := client.Get("/x")
+		var apiErr *odin_api.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("err = %v, want *APIError", err)
+		}
+		if apiErr.RetryAfter < tt.min || apiErr.RetryAfter > tt.max {
+			t.Errorf("Retry-After %q = %v, want [%v, %v]", tt.header, apiErr.RetryAfter, tt.min, tt.max)
+		}
+	}
+}
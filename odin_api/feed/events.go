@@ -0,0 +1,100 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+// Channel 可订阅的事件频道
+type Channel string
+
+const (
+	ChannelTrades Channel = "trades" // 成交事件
+	ChannelTokens Channel = "tokens" // 新代币事件
+	ChannelPrices Channel = "prices" // 价格更新事件
+)
+
+// Subscription 一个订阅，TokenID为空表示订阅该频道的全部代币
+type Subscription struct {
+	Channel Channel `json:"channel"`
+	TokenID string  `json:"token,omitempty"`
+}
+
+// EventType 事件类型
+type EventType string
+
+const (
+	EventTrade    EventType = "trade"
+	EventNewToken EventType = "token"
+	EventPrice    EventType = "price"
+)
+
+// Event 从推送流中解码出的市场事件
+// Trade和Token中最多有一个非空，未知类型的事件仅保留Raw
+type Event struct {
+	Type    EventType
+	Channel Channel
+	TokenID string
+	Trade   *odin_api.Trade       // EventTrade
+	Token   *odin_api.TokenDetail // EventNewToken、EventPrice
+	Raw     json.RawMessage       // 事件的原始data字段
+}
+
+// envelope 推送消息的外层结构
+type envelope struct {
+	Type    EventType       `json:"type"`
+	Channel Channel         `json:"channel"`
+	Token   string          `json:"token"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// request 发送给服务器的订阅请求
+type request struct {
+	Action string `json:"action"`
+	Subscription
+}
+
+// encodeRequest 编码订阅或取消订阅请求
+func encodeRequest(action string, sub Subscription) ([]byte, error) {
+	return json.Marshal(request{Action: action, Subscription: sub})
+}
+
+// decodeEvent 将一条推送消息解码为Event
+func decodeEvent(message []byte) (Event, error) {
+	var env envelope
+	if err := json.Unmarshal(message, &env); err != nil {
+		return Event{}, fmt.Errorf("解析推送消息失败: %w", err)
+	}
+
+	event := Event{
+		Type:    env.Type,
+		Channel: env.Channel,
+		TokenID: env.Token,
+		Raw:     env.Data,
+	}
+
+	switch env.Type {
+	case EventTrade:
+		var trade odin_api.Trade
+		if err := json.Unmarshal(env.Data, &trade); err != nil {
+			return Event{}, fmt.Errorf("解析成交事件失败: %w", err)
+		}
+		if event.TokenID == "" {
+			event.TokenID = trade.Token
+		}
+		event.Trade = &trade
+	case EventNewToken, EventPrice:
+		var token odin_api.TokenDetail
+		if err := json.Unmarshal(env.Data, &token); err != nil {
+			return Event{}, fmt.Errorf("解析代币事件失败: %w", err)
+		}
+		if event.TokenID == "" {
+			event.TokenID = token.ID
+		}
+		event.Token = &token
+	}
+
+	return event, nil
+}
@@ -0,0 +1,231 @@
+// Package feed 提供Odin.fun实时市场事件推送的WebSocket客户端
+//
+// Feed在连接断开后会按指数退避自动重连，并在重连后重新发送所有订阅。
+// 推送消息的格式为 {"type": "...", "channel": "...", "token": "...", "data": {...}}，
+// 其中data按事件类型解码为odin_api.Trade或odin_api.TokenDetail。
+//
+// Odin.fun没有公开推送接口的文档，上述消息格式以及订阅请求
+// {"action": "subscribe", "channel": "...", "token": "..."} 是本包假定的协议，
+// 因此Config.URL没有默认值。实际服务的格式不同时，可以通过Config.Dial包装Conn进行转换。
+// odintest.FeedServer实现了同样的协议，可用于离线测试。
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Config Feed的配置
+type Config struct {
+	URL          string        // WebSocket地址，例如 wss://example.com/ws
+	Header       http.Header   // 握手时附加的请求头，例如Authorization
+	MinBackoff   time.Duration // 重连的初始等待时间，默认500毫秒
+	MaxBackoff   time.Duration // 重连等待时间的上限，默认30秒
+	StableAfter  time.Duration // 连接保持多久视为稳定，默认10秒；连接稳定或收到过消息后才重置退避时间
+	PingInterval time.Duration // 心跳间隔，默认20秒；超过两个间隔未收到数据视为断线
+	Buffer       int           // 事件通道的缓冲区大小，默认64
+
+	// Dial 自定义连接方式，为nil时使用标准库实现的WebSocket客户端
+	Dial Dialer
+	// OnError 连接、解码或订阅失败时调用
+	OnError func(err error)
+}
+
+// Feed 实时市场事件客户端，可并发调用Subscribe和Unsubscribe
+type Feed struct {
+	cfg    Config
+	events chan Event
+
+	mu   sync.Mutex
+	subs map[Subscription]struct{}
+	conn Conn
+}
+
+// New 创建一个Feed，调用Run开始连接和接收事件
+func New(cfg Config) *Feed {
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = 500 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 30 * time.Second
+	}
+	if cfg.StableAfter <= 0 {
+		cfg.StableAfter = 10 * time.Second
+	}
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = 20 * time.Second
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = 64
+	}
+	if cfg.Dial == nil {
+		readTimeout := 2 * cfg.PingInterval
+		cfg.Dial = func(ctx context.Context, url string, header http.Header) (Conn, error) {
+			return dial(ctx, url, header, readTimeout)
+		}
+	}
+
+	return &Feed{
+		cfg:    cfg,
+		events: make(chan Event, cfg.Buffer),
+		subs:   make(map[Subscription]struct{}),
+	}
+}
+
+// Events 返回事件通道，Run返回后通道会被关闭
+func (f *Feed) Events() <-chan Event {
+	return f.events
+}
+
+// Subscribe 添加订阅，已连接时立即发送，断线重连后会自动重新订阅
+func (f *Feed) Subscribe(subs ...Subscription) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, sub := range subs {
+		f.subs[sub] = struct{}{}
+	}
+	if f.conn == nil {
+		return nil
+	}
+	return sendAll(f.conn, "subscribe", subs)
+}
+
+// Unsubscribe 取消订阅
+func (f *Feed) Unsubscribe(subs ...Subscription) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, sub := range subs {
+		delete(f.subs, sub)
+	}
+	if f.conn == nil {
+		return nil
+	}
+	return sendAll(f.conn, "unsubscribe", subs)
+}
+
+// Run 连接并持续接收事件，断线时自动重连，直到ctx被取消时返回ctx.Err()
+func (f *Feed) Run(ctx context.Context) error {
+	defer close(f.events)
+
+	backoff := f.cfg.MinBackoff
+	for {
+		healthy, err := f.session(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			f.reportError(err)
+		}
+		if healthy {
+			// 连接建立后立即被关闭的情况不重置，避免以最小间隔反复重连
+			backoff = f.cfg.MinBackoff
+		}
+
+		wait := backoff/2 + rand.N(backoff/2+1)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, f.cfg.MaxBackoff)
+	}
+}
+
+// session 建立一次连接并读取事件直到连接断开
+// healthy表示连接收到过消息或保持了StableAfter以上，用于重置退避时间
+func (f *Feed) session(ctx context.Context) (healthy bool, err error) {
+	conn, err := f.cfg.Dial(ctx, f.cfg.URL, f.cfg.Header)
+	if err != nil {
+		return false, fmt.Errorf("连接推送服务失败: %w", err)
+	}
+	start := time.Now()
+	received := false
+
+	f.mu.Lock()
+	subs := make([]Subscription, 0, len(f.subs))
+	for sub := range f.subs {
+		subs = append(subs, sub)
+	}
+	if err := sendAll(conn, "subscribe", subs); err != nil {
+		f.mu.Unlock()
+		conn.Close()
+		return false, err
+	}
+	f.conn = conn
+	f.mu.Unlock()
+
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		f.mu.Lock()
+		f.conn = nil
+		f.mu.Unlock()
+		conn.Close()
+	}()
+
+	// ctx取消时关闭连接以中断阻塞的读取，同时定期发送心跳
+	go func() {
+		ticker := time.NewTicker(f.cfg.PingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sessionCtx.Done():
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := conn.Ping(); err != nil {
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		message, err := conn.ReadMessage()
+		if err != nil {
+			return received || time.Since(start) >= f.cfg.StableAfter, fmt.Errorf("读取推送消息失败: %w", err)
+		}
+		received = true
+
+		event, err := decodeEvent(message)
+		if err != nil {
+			f.reportError(err)
+			continue
+		}
+
+		select {
+		case f.events <- event:
+		case <-ctx.Done():
+			return received, ctx.Err()
+		}
+	}
+}
+
+// reportError 调用OnError回调
+func (f *Feed) reportError(err error) {
+	if f.cfg.OnError != nil && !errors.Is(err, context.Canceled) {
+		f.cfg.OnError(err)
+	}
+}
+
+// sendAll 逐个发送订阅请求
+func sendAll(conn Conn, action string, subs []Subscription) error {
+	for _, sub := range subs {
+		data, err := encodeRequest(action, sub)
+		if err != nil {
+			return fmt.Errorf("编码订阅请求失败: %w", err)
+		}
+		if err := conn.WriteMessage(data); err != nil {
+			return fmt.Errorf("发送订阅请求失败: %w", err)
+		}
+	}
+	return nil
+}
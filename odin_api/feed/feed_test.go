@@ -0,0 +1,109 @@
+package feed_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/feed"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+// nextEvent 读取下一个事件，超时时测试失败
+func nextEvent(t *testing.T, f *feed.Feed) feed.Event {
+	t.Helper()
+
+	select {
+	case event, ok := <-f.Events():
+		if !ok {
+			t.Fatal("event channel closed")
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return feed.Event{}
+}
+
+func TestFeedReceivesAndResubscribes(t *testing.T) {
+	srv := odintest.NewFeedServer()
+	defer srv.Close()
+
+	sub := feed.Subscription{Channel: feed.ChannelTrades, TokenID: "2jjj"}
+	f := feed.New(feed.Config{URL: srv.WebSocketURL(), MinBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+	if err := f.Subscribe(sub); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- f.Run(ctx) }()
+
+	if err := srv.WaitSubscribed(ctx, sub); err != nil {
+		t.Fatalf("WaitSubscribed: %v", err)
+	}
+	// 未订阅的代币不会推送给客户端
+	if n, err := srv.PublishTrade(odin_api.Trade{ID: "x", Token: "other"}); err != nil || n != 0 {
+		t.Fatalf("PublishTrade(other) = %d, %v, want 0 receivers", n, err)
+	}
+	if n, err := srv.PublishTrade(odin_api.Trade{ID: "t1", Token: "2jjj"}); err != nil || n != 1 {
+		t.Fatalf("PublishTrade = %d, %v, want 1 receiver", n, err)
+	}
+	event := nextEvent(t, f)
+	if event.Type != feed.EventTrade || event.TokenID != "2jjj" || event.Trade == nil || event.Trade.ID != "t1" {
+		t.Fatalf("event = %+v, want trade t1", event)
+	}
+
+	// 断线后自动重连并恢复订阅
+	srv.DropConnections()
+	for srv.Connections() < 2 {
+		if ctx.Err() != nil {
+			t.Fatal("feed did not reconnect")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := srv.WaitSubscribed(ctx, sub); err != nil {
+		t.Fatalf("WaitSubscribed after reconnect: %v", err)
+	}
+	if _, err := srv.PublishTrade(odin_api.Trade{ID: "t2", Token: "2jjj"}); err != nil {
+		t.Fatal(err)
+	}
+	if event := nextEvent(t, f); event.Trade == nil || event.Trade.ID != "t2" {
+		t.Fatalf("event after reconnect = %+v, want trade t2", event)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run = %v, want context.Canceled", err)
+	}
+}
+
+func TestFeedBacksOffWhenConnectionsFail(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	f := feed.New(feed.Config{
+		URL:        "ws" + strings.TrimPrefix(srv.URL, "http"),
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: time.Second,
+		OnError:    func(error) {},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	f.Run(ctx)
+
+	// 以指数退避重连，300毫秒内最多连接约6次；不退避时会接近30次
+	if n := attempts.Load(); n < 2 || n > 8 {
+		t.Errorf("attempts = %d, want between 2 and 8", n)
+	}
+}
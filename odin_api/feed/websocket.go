@@ -0,0 +1,248 @@
+package feed
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MrHat365/odin-go/internal/websocket"
+)
+
+// maxMessageSize 单条消息的最大字节数
+const maxMessageSize = 16 << 20
+
+// Conn 是Feed使用的消息连接，便于在测试中替换为本地实现
+type Conn interface {
+	// ReadMessage 读取下一条完整的文本或二进制消息
+	ReadMessage() ([]byte, error)
+	// WriteMessage 发送一条文本消息
+	WriteMessage(data []byte) error
+	// Ping 发送心跳
+	Ping() error
+	// Close 关闭连接
+	Close() error
+}
+
+// Dialer 建立到url的连接
+type Dialer func(ctx context.Context, url string, header http.Header) (Conn, error)
+
+// CloseError 表示对端发送了关闭帧
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error 实现error接口
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket连接已关闭，代码: %d, 原因: %s", e.Code, e.Reason)
+}
+
+// wsConn 基于标准库实现的最小WebSocket客户端连接（RFC 6455）
+type wsConn struct {
+	conn        net.Conn
+	br          *bufio.Reader
+	readTimeout time.Duration
+
+	writeMu sync.Mutex
+}
+
+// Dial 使用标准库建立WebSocket连接，支持ws和wss
+func Dial(ctx context.Context, rawURL string, header http.Header) (Conn, error) {
+	return dial(ctx, rawURL, header, 0)
+}
+
+// dial 建立WebSocket连接，readTimeout大于0时每次读取都会设置超时
+func dial(ctx context.Context, rawURL string, header http.Header, readTimeout time.Duration) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("解析WebSocket地址失败: %w", err)
+	}
+
+	var secure bool
+	switch u.Scheme {
+	case "ws":
+	case "wss":
+		secure = true
+	default:
+		return nil, fmt.Errorf("不支持的WebSocket协议: %s", u.Scheme)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		if secure {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("连接WebSocket服务器失败: %w", err)
+	}
+	if secure {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("TLS握手失败: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	ws, err := handshake(ctx, conn, u, header)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	ws.readTimeout = readTimeout
+	return ws, nil
+}
+
+// handshake 发送升级请求并校验服务器响应
+func handshake(ctx context.Context, conn net.Conn, u *url.URL, header http.Header) (*wsConn, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成握手密钥失败: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Host:       u.Host,
+	}
+	for k, values := range header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err := req.Write(conn); err != nil {
+		return nil, fmt.Errorf("发送握手请求失败: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, fmt.Errorf("读取握手响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("WebSocket握手失败，状态码: %d", resp.StatusCode)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		return nil, errors.New("WebSocket握手失败: 缺少Upgrade响应头")
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != websocket.AcceptKey(key) {
+		return nil, errors.New("WebSocket握手失败: Sec-WebSocket-Accept不匹配")
+	}
+
+	return &wsConn{conn: conn, br: br}, nil
+}
+
+// ReadMessage 读取下一条完整消息，自动应答ping并合并分片
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	var started bool
+
+	for {
+		if c.readTimeout > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+		}
+
+		frame, err := websocket.ReadFrame(c.br, maxMessageSize)
+		if err != nil {
+			return nil, err
+		}
+		payload := frame.Payload
+
+		switch frame.Opcode {
+		case websocket.OpPing:
+			if err := c.writeFrame(websocket.OpPong, payload); err != nil {
+				return nil, err
+			}
+		case websocket.OpPong:
+		case websocket.OpClose:
+			closeErr := &CloseError{Code: 1005}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			c.writeFrame(websocket.OpClose, payload)
+			return nil, closeErr
+		case websocket.OpText, websocket.OpBinary:
+			if started {
+				return nil, errors.New("WebSocket协议错误: 分片消息未结束")
+			}
+			started = true
+			message = append(message[:0], payload...)
+		case websocket.OpContinuation:
+			if !started {
+				return nil, errors.New("WebSocket协议错误: 意外的续帧")
+			}
+			message = append(message, payload...)
+		default:
+			return nil, fmt.Errorf("WebSocket协议错误: 未知的操作码 %d", frame.Opcode)
+		}
+
+		if len(message) > maxMessageSize {
+			return nil, errors.New("WebSocket消息过大")
+		}
+		if started && frame.Fin {
+			return message, nil
+		}
+	}
+}
+
+// WriteMessage 发送一条文本消息
+func (c *wsConn) WriteMessage(data []byte) error {
+	return c.writeFrame(websocket.OpText, data)
+}
+
+// Ping 发送ping帧
+func (c *wsConn) Ping() error {
+	return c.writeFrame(websocket.OpPing, nil)
+}
+
+// Close 发送关闭帧并关闭底层连接
+func (c *wsConn) Close() error {
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, 1000)
+	c.writeFrame(websocket.OpClose, payload)
+	return c.conn.Close()
+}
+
+// writeFrame 发送一个带掩码的完整帧，客户端发送的帧必须加掩码
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if err := websocket.WriteFrame(c.conn, opcode, payload, true); err != nil {
+		return fmt.Errorf("发送WebSocket帧失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package odin_api
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/aviate-labs/agent-go/identity"
+	"github.com/aviate-labs/agent-go/principal"
+	"github.com/aviate-labs/secp256k1"
+)
+
+// KeyIdentity 基于agent-go身份实现的Identity
+// 同一个密钥既可用于odin_api认证，也可通过AgentIdentity用于创建agent_sdk所需的Agent
+type KeyIdentity struct {
+	id identity.Identity
+}
+
+// NewKeyIdentity 包装任意agent-go身份
+func NewKeyIdentity(id identity.Identity) *KeyIdentity {
+	return &KeyIdentity{id: id}
+}
+
+// GetPublicKey 返回DER编码的公钥，实现Identity接口
+func (k *KeyIdentity) GetPublicKey() []byte {
+	return k.id.PublicKey()
+}
+
+// Sign 对消息签名，实现Identity接口
+func (k *KeyIdentity) Sign(message []byte) ([]byte, error) {
+	return k.id.Sign(message), nil
+}
+
+// AgentIdentity 返回底层的agent-go身份，可用于agent.Config.Identity
+func (k *KeyIdentity) AgentIdentity() identity.Identity {
+	return k.id
+}
+
+// Principal 返回身份的自认证Principal
+func (k *KeyIdentity) Principal() principal.Principal {
+	return k.id.Sender()
+}
+
+// PrincipalText 返回身份的自认证Principal文本，即Odin.fun的用户ID
+func (k *KeyIdentity) PrincipalText() string {
+	return k.id.Sender().String()
+}
+
+// ToPEM 将私钥导出为PEM格式
+func (k *KeyIdentity) ToPEM() ([]byte, error) {
+	return k.id.ToPEM()
+}
+
+// String 只返回Principal，避免私钥出现在格式化输出中
+func (k *KeyIdentity) String() string {
+	return "KeyIdentity(" + k.PrincipalText() + ")"
+}
+
+// LogValue 实现slog.LogValuer，日志中只记录Principal
+func (k *KeyIdentity) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("principal", k.PrincipalText()))
+}
+
+// PrincipalText 根据Identity的DER公钥计算自认证Principal文本
+func PrincipalText(id Identity) string {
+	return principal.NewSelfAuthenticating(id.GetPublicKey()).String()
+}
+
+// NewEd25519Identity 从Ed25519私钥创建身份
+func NewEd25519Identity(privateKey ed25519.PrivateKey) (*KeyIdentity, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("无效的Ed25519私钥长度: %d", len(privateKey))
+	}
+	id, err := identity.NewEd25519Identity(privateKey.Public().(ed25519.PublicKey), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("创建Ed25519身份失败: %w", err)
+	}
+	return NewKeyIdentity(id), nil
+}
+
+// NewRandomEd25519Identity 生成随机的Ed25519身份
+func NewRandomEd25519Identity() (*KeyIdentity, error) {
+	id, err := identity.NewRandomEd25519Identity()
+	if err != nil {
+		return nil, fmt.Errorf("生成Ed25519身份失败: %w", err)
+	}
+	return NewKeyIdentity(id), nil
+}
+
+// NewEd25519IdentityFromHex 从十六进制私钥创建Ed25519身份
+// 支持32字节的种子或64字节的完整私钥
+func NewEd25519IdentityFromHex(privateKeyHex string) (*KeyIdentity, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(privateKeyHex))
+	if err != nil {
+		return nil, fmt.Errorf("解析私钥失败: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return NewEd25519Identity(ed25519.NewKeyFromSeed(raw))
+	case ed25519.PrivateKeySize:
+		return NewEd25519Identity(ed25519.PrivateKey(raw))
+	default:
+		return nil, fmt.Errorf("无效的Ed25519私钥长度: %d", len(raw))
+	}
+}
+
+// NewSecp256k1Identity 从32字节的secp256k1私钥创建身份
+func NewSecp256k1Identity(privateKey []byte) (*KeyIdentity, error) {
+	if len(privateKey) != 32 {
+		return nil, fmt.Errorf("无效的secp256k1私钥长度: %d", len(privateKey))
+	}
+	key, _ := secp256k1.PrivKeyFromBytes(secp256k1.S256(), privateKey)
+	id, err := identity.NewSecp256k1Identity(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建secp256k1身份失败: %w", err)
+	}
+	return NewKeyIdentity(id), nil
+}
+
+// NewRandomSecp256k1Identity 生成随机的secp256k1身份
+func NewRandomSecp256k1Identity() (*KeyIdentity, error) {
+	id, err := identity.NewRandomSecp256k1Identity()
+	if err != nil {
+		return nil, fmt.Errorf("生成secp256k1身份失败: %w", err)
+	}
+	return NewKeyIdentity(id), nil
+}
+
+// NewSecp256k1IdentityFromHex 从十六进制的secp256k1私钥创建身份
+func NewSecp256k1IdentityFromHex(privateKeyHex string) (*KeyIdentity, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(privateKeyHex))
+	if err != nil {
+		return nil, fmt.Errorf("解析私钥失败: %w", err)
+	}
+	return NewSecp256k1Identity(raw)
+}
+
+// LoadPEMIdentity 从PEM数据加载身份
+// 支持dfx导出的Ed25519（PKCS#8）和secp256k1（带或不带EC PARAMETERS）密钥，以及prime256v1密钥
+func LoadPEMIdentity(data []byte) (*KeyIdentity, error) {
+	if id, err := identity.NewEd25519IdentityFromPEM(data); err == nil {
+		return NewKeyIdentity(id), nil
+	}
+	if id, err := identity.NewSecp256k1IdentityFromPEM(data); err == nil {
+		return NewKeyIdentity(id), nil
+	}
+	if id, err := identity.NewSecp256k1IdentityFromPEMWithoutParameters(data); err == nil {
+		return NewKeyIdentity(id), nil
+	}
+	if id, err := identity.NewPrime256v1IdentityFromPEM(data); err == nil {
+		return NewKeyIdentity(id), nil
+	}
+	return nil, errors.New("无法识别的PEM私钥格式")
+}
+
+// LoadPEMIdentityFile 从PEM文件加载身份，例如 ~/.config/dfx/identity/default/identity.pem
+func LoadPEMIdentityFile(path string) (*KeyIdentity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取PEM文件失败: %w", err)
+	}
+	return LoadPEMIdentity(data)
+}
@@ -0,0 +1,99 @@
+package odin_api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// redactedValue 替换敏感内容后记录的值
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders 记录日志时总是被替换的请求头
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// WithLogger 设置客户端的日志记录器
+// 请求方法、端点、状态码、耗时和重试以Debug级别记录，Authorization等请求头总是被替换为[REDACTED]。
+// 未设置时不记录日志。
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// redactedHeader 记录日志时隐藏敏感值的请求头
+type redactedHeader http.Header
+
+// LogValue 实现slog.LogValuer
+func (h redactedHeader) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(h))
+	for key, values := range h {
+		value := slog.AnyValue(values)
+		for _, name := range sensitiveHeaders {
+			if http.CanonicalHeaderKey(key) == name {
+				value = slog.StringValue(redactedValue)
+				break
+			}
+		}
+		attrs = append(attrs, slog.Attr{Key: key, Value: value})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LoggingMiddleware 以Debug级别记录每次请求的方法、端点、尝试序号、状态码和耗时
+// 请求头中的敏感值会被替换为[REDACTED]
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			if !logger.Enabled(ctx, slog.LevelDebug) {
+				return next.Do(req)
+			}
+
+			start := time.Now()
+			resp, err := next.Do(req)
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("endpoint", RequestEndpoint(req)),
+				slog.Int("attempt", RequestAttempt(req)),
+				slog.Duration("latency", time.Since(start)),
+				slog.Any("header", redactedHeader(req.Header)),
+			}
+			if status := statusOf(resp, err); status != 0 {
+				attrs = append(attrs, slog.Int("status", status))
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			logger.LogAttrs(ctx, slog.LevelDebug, "odin_api请求", attrs...)
+			return resp, err
+		})
+	}
+}
+
+// logRetry 记录即将进行的重试
+func (c *Client) logRetry(a RetryAttempt) {
+	c.logger.LogAttrs(context.Background(), slog.LevelDebug, "odin_api重试",
+		slog.String("method", a.Method),
+		slog.String("endpoint", a.Endpoint),
+		slog.Int("attempt", a.Attempt),
+		slog.Duration("delay", a.Delay),
+	)
+}
+
+// statusOf 返回响应或错误中的HTTP状态码，请求未得到响应时返回0
+func statusOf(resp *http.Response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
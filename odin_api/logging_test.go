@@ -0,0 +1,149 @@
+package odin_api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+// debugLogger 返回把Debug及以上级别的JSON日志写入buf的记录器
+func debugLogger(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: level}))
+}
+
+// logRecords 解析JSON日志，每行一条记录
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLoggingRedactsSensitiveHeaders(t *testing.T) {
+	srv := newServer(t)
+	var buf bytes.Buffer
+	client := srv.NewClient(
+		odin_api.WithLogger(debugLogger(&buf, slog.LevelDebug)),
+		odin_api.WithHeader("Cookie", "session=cookie-secret"),
+		odin_api.WithHeader("X-Trace", "visible"),
+	)
+	client.SetToken("token-secret")
+
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{"token-secret", "cookie-secret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log contains %q: %s", secret, out)
+		}
+	}
+	records := logRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("records = %d, want 1", len(records))
+	}
+	record := records[0]
+	if record["method"] != "GET" || record["endpoint"] != "/token/2jjj" || record["status"] != float64(200) {
+		t.Errorf("record = %v, want GET /token/2jjj 200", record)
+	}
+	header, _ := record["header"].(map[string]any)
+	if header["Cookie"] != "[REDACTED]" {
+		t.Errorf("header = %v, want Cookie redacted", header)
+	}
+	if !strings.Contains(out, "visible") {
+		t.Errorf("header = %v, want X-Trace logged", header)
+	}
+}
+
+func TestLoggingMiddlewareRedactsAuthorization(t *testing.T) {
+	var buf bytes.Buffer
+	doer := odin_api.LoggingMiddleware(debugLogger(&buf, slog.LevelDebug))(odin_api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+	}))
+
+	req, _ := http.NewRequest(http.MethodPost, "http://odin.test/v1/auth", nil)
+	req.Header.Set("Authorization", "Bearer token-secret")
+	req.Header.Set("Proxy-Authorization", "Basic proxy-secret")
+	if _, err := doer.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("log contains a secret: %s", buf.String())
+	}
+	header, _ := logRecords(t, &buf)[0]["header"].(map[string]any)
+	if header["Authorization"] != "[REDACTED]" || header["Proxy-Authorization"] != "[REDACTED]" {
+		t.Errorf("header = %v, want Authorization and Proxy-Authorization redacted", header)
+	}
+}
+
+func TestLoggingRetries(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{Status: http.StatusServiceUnavailable, Times: 1})
+	var buf bytes.Buffer
+	client := srv.NewClient(
+		odin_api.WithLogger(debugLogger(&buf, slog.LevelDebug)),
+		odin_api.WithRetryPolicy(odin_api.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+
+	var messages []string
+	var attempts []float64
+	for _, record := range logRecords(t, &buf) {
+		messages = append(messages, record["msg"].(string))
+		if a, ok := record["attempt"].(float64); ok && record["msg"] == "odin_api请求" {
+			attempts = append(attempts, a)
+		}
+	}
+	if strings.Join(messages, ",") != "odin_api请求,odin_api重试,odin_api请求" {
+		t.Errorf("messages = %v, want request, retry, request", messages)
+	}
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("attempts = %v, want [1 2]", attempts)
+	}
+}
+
+func TestLoggingDisabledAboveDebug(t *testing.T) {
+	srv := newServer(t)
+	var buf bytes.Buffer
+	client := srv.NewClient(odin_api.WithLogger(debugLogger(&buf, slog.LevelInfo)))
+
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("log = %s, want nothing at info level", buf.String())
+	}
+}
+
+func TestIdentityLogValue(t *testing.T) {
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	debugLogger(&buf, slog.LevelDebug).Info("auth", "identity", identity)
+
+	records := logRecords(t, &buf)
+	logged, _ := records[0]["identity"].(map[string]any)
+	if len(logged) != 1 || logged["principal"] != identity.PrincipalText() {
+		t.Errorf("identity = %v, want only the principal", logged)
+	}
+}
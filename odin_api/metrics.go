@@ -0,0 +1,38 @@
+package odin_api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MrHat365/odin-go/metrics"
+)
+
+// WithMetrics 设置指标记录器，记录请求次数、耗时、重试、限流等待和解析失败
+// 端点标签中的用户和代币ID会被替换为{id}，避免产生过多时间序列
+func WithMetrics(recorder metrics.Recorder) Option {
+	return func(c *Client) {
+		c.metrics = recorder
+	}
+}
+
+// MetricsMiddleware 记录每次请求的次数和耗时
+// status标签为HTTP状态码，请求未得到响应时为error
+func MetricsMiddleware(recorder metrics.Recorder) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+
+			endpoint := metrics.L("endpoint", routeOf(RequestEndpoint(req)))
+			method := metrics.L("method", req.Method)
+			status := "error"
+			if code := statusOf(resp, err); code != 0 {
+				status = strconv.Itoa(code)
+			}
+			recorder.Add(metrics.RequestsTotal, 1, endpoint, method, metrics.L("status", status))
+			recorder.Observe(metrics.RequestDuration, time.Since(start).Seconds(), endpoint, method)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package odin_api_test
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/metrics"
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+// metricsText 返回Registry的Prometheus文本输出
+func metricsText(t *testing.T, r *metrics.Registry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestClientMetrics(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{Status: http.StatusServiceUnavailable, Times: 1})
+	registry := metrics.NewRegistry()
+	client := srv.NewClient(
+		odin_api.WithMetrics(registry),
+		odin_api.WithRetryPolicy(odin_api.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+
+	text := metricsText(t, registry)
+	for _, want := range []string{
+		`odin_api_requests_total{endpoint="/token/{id}",method="GET",status="200"} 1`,
+		`odin_api_requests_total{endpoint="/token/{id}",method="GET",status="503"} 1`,
+		`odin_api_retries_total{endpoint="/token/{id}",method="GET"} 1`,
+		`odin_api_request_duration_seconds_count{endpoint="/token/{id}",method="GET"} 2`,
+	} {
+		if !strings.Contains(text, want+"\n") {
+			t.Errorf("metrics missing %s:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "2jjj") {
+		t.Errorf("metrics contain the raw token ID:\n%s", text)
+	}
+}
+
+func TestClientMetricsDecodeErrors(t *testing.T) {
+	registry := metrics.NewRegistry()
+	client := statusServer(t, http.StatusOK, nil, `not json`)
+	client = odin_api.NewClient(odin_api.WithBaseURL(client.BaseURL()), odin_api.WithMetrics(registry))
+
+	if _, err := client.GetOdinFunUser("abc"); err == nil {
+		t.Fatal("GetOdinFunUser succeeded, want decode error")
+	}
+	text := metricsText(t, registry)
+	if want := `odin_api_decode_errors_total{endpoint="/user/{id}"} 1`; !strings.Contains(text, want+"\n") {
+		t.Errorf("metrics missing %s:\n%s", want, text)
+	}
+}
@@ -0,0 +1,185 @@
+package odin_api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/MrHat365/odin-go/metrics"
+)
+
+// Doer 发送HTTP请求，*http.Client实现了该接口
+//
+// 请求管道中最内层的Doer会读取完整的响应体，并将非2xx状态码转换为*APIError，
+// 因此中间件看到的成功响应总是2xx，且响应体可以安全地读取。
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc 将函数适配为Doer
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do 调用f(req)
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware 包装Doer，在请求发送前后执行额外逻辑，例如添加请求头、签名或统计
+type Middleware func(next Doer) Doer
+
+// Chain 将多个中间件组合为一个，第一个中间件位于最外层
+func Chain(middleware ...Middleware) Middleware {
+	return func(next Doer) Doer {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+// WithMiddleware 追加自定义中间件
+//
+// 客户端的请求管道从外到内依次为：重试、限流（包括批量请求的共享暂停）、指标、日志、授权、自定义中间件、发送请求。
+// 自定义中间件在每次尝试时都会执行，看到的是已设置Authorization的最终请求，
+// 因此适合添加追踪请求头、请求签名或统计每次尝试。
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// contextKey 请求管道在context中保存的信息
+type contextKey int
+
+const (
+	endpointKey contextKey = iota
+	attemptKey
+	batchKey
+)
+
+// RequestEndpoint 返回请求对应的端点，即相对于BaseURL的路径和查询参数，例如 /token/{id}/trades?page=1
+// 请求不是由Client发出时返回URL的路径和查询参数
+func RequestEndpoint(req *http.Request) string {
+	if endpoint, ok := req.Context().Value(endpointKey).(string); ok {
+		return endpoint
+	}
+	return req.URL.RequestURI()
+}
+
+// RequestAttempt 返回请求在重试中的尝试序号，从1开始
+func RequestAttempt(req *http.Request) int {
+	if attempt, ok := req.Context().Value(attemptKey).(int); ok {
+		return attempt
+	}
+	return 1
+}
+
+// AuthMiddleware 为每个请求设置Bearer令牌，token返回空字符串时不设置Authorization
+func AuthMiddleware(token func(ctx context.Context) (string, error)) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			t, err := token(req.Context())
+			if err != nil {
+				return nil, fmt.Errorf("获取授权令牌失败: %w", err)
+			}
+			if t == "" {
+				return next.Do(req)
+			}
+			return next.Do(withBearer(req, t))
+		})
+	}
+}
+
+// withBearer 返回设置了Bearer令牌的请求副本
+func withBearer(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+// bearerToken 返回请求中的Bearer令牌
+func bearerToken(req *http.Request) string {
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+}
+
+// rewind 返回可以再次发送的请求副本
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("重建请求体失败: %w", err)
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// pipeline 按照客户端的配置组装请求管道，在创建客户端和绑定Session时调用一次
+func (c *Client) pipeline() Doer {
+	retry := c.retry
+	onAttempt := retry.OnAttempt
+	retry.OnAttempt = func(a RetryAttempt) {
+		if onAttempt != nil {
+			onAttempt(a)
+		}
+		if a.Retrying {
+			c.logRetry(a)
+			if c.metrics != nil {
+				c.metrics.Add(metrics.RetriesTotal, 1,
+					metrics.L("endpoint", routeOf(a.Endpoint)), metrics.L("method", a.Method))
+			}
+		}
+	}
+
+	middleware := []Middleware{RetryMiddleware(retry), batchMiddleware}
+	if c.limiter != nil {
+		middleware = append(middleware, limitMiddleware("", c.limiter, c.metrics))
+	}
+	for _, group := range slices.Sorted(maps.Keys(c.groupLimiters)) {
+		middleware = append(middleware, limitMiddleware(group, c.groupLimiters[group], c.metrics))
+	}
+	if c.metrics != nil {
+		middleware = append(middleware, MetricsMiddleware(c.metrics))
+	}
+	middleware = append(middleware, LoggingMiddleware(c.logger))
+	if c.session != nil {
+		middleware = append(middleware, SessionMiddleware(c.session))
+	} else {
+		middleware = append(middleware, AuthMiddleware(func(context.Context) (string, error) {
+			return c.Token, nil
+		}))
+	}
+	middleware = append(middleware, c.middleware...)
+
+	return Chain(middleware...)(c.transport())
+}
+
+// transport 管道最内层的Doer，发送请求并读取完整的响应体
+func (c *Client) transport() Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("发送请求失败: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("读取响应失败: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, newAPIError(req.Method, RequestEndpoint(req), resp, body)
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+		return resp, nil
+	})
+}
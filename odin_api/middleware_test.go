@@ -0,0 +1,139 @@
+package odin_api_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+// traceMiddleware 在请求前后把name记录到trace中
+func traceMiddleware(trace *[]string, name string) odin_api.Middleware {
+	return func(next odin_api.Doer) odin_api.Doer {
+		return odin_api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			*trace = append(*trace, name+" before")
+			resp, err := next.Do(req)
+			*trace = append(*trace, name+" after")
+			return resp, err
+		})
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var trace []string
+	doer := odin_api.Chain(traceMiddleware(&trace, "a"), traceMiddleware(&trace, "b"))(odin_api.DoerFunc(func(*http.Request) (*http.Response, error) {
+		trace = append(trace, "send")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://odin.test/", nil)
+	if _, err := doer.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(trace, ", "); got != "a before, b before, send, b after, a after" {
+		t.Errorf("trace = %s", got)
+	}
+}
+
+func TestWithMiddlewareRunsPerAttemptWithAuthorization(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{Status: http.StatusServiceUnavailable, Times: 1})
+
+	type seen struct {
+		endpoint string
+		attempt  int
+		auth     string
+	}
+	var (
+		trace    []string
+		requests []seen
+	)
+	client := srv.NewClient(
+		odin_api.WithRetryPolicy(odin_api.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		odin_api.WithMiddleware(traceMiddleware(&trace, "first")),
+		odin_api.WithMiddleware(
+			traceMiddleware(&trace, "second"),
+			func(next odin_api.Doer) odin_api.Doer {
+				return odin_api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+					requests = append(requests, seen{
+						endpoint: odin_api.RequestEndpoint(req),
+						attempt:  odin_api.RequestAttempt(req),
+						auth:     req.Header.Get("Authorization"),
+					})
+					return next.Do(req)
+				})
+			},
+		),
+	)
+	client.SetToken("secret")
+
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+
+	want := []seen{{"/token/2jjj", 1, "Bearer secret"}, {"/token/2jjj", 2, "Bearer secret"}}
+	if len(requests) != len(want) {
+		t.Fatalf("requests = %+v, want %+v", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("request %d = %+v, want %+v", i, requests[i], want[i])
+		}
+	}
+	got := strings.Join(trace, ", ")
+	if got != "first before, second before, second after, first after, first before, second before, second after, first after" {
+		t.Errorf("trace = %s", got)
+	}
+}
+
+func TestMiddlewareSeesAPIErrors(t *testing.T) {
+	var got error
+	client := statusServer(t, http.StatusNotFound, nil, `{"message":"not found"}`)
+	client = odin_api.NewClient(
+		odin_api.WithBaseURL(client.BaseURL()),
+		odin_api.WithMiddleware(func(next odin_api.Doer) odin_api.Doer {
+			return odin_api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				resp, err := next.Do(req)
+				got = err
+				return resp, err
+			})
+		}),
+	)
+
+	if _, err := client.GetOdinFunToken("missing"); !errors.Is(err, odin_api.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	var apiErr *odin_api.APIError
+	if !errors.As(got, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("middleware err = %v, want *APIError 404", got)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	srv := newServer(t)
+	client := srv.NewClient(odin_api.WithMiddleware(func(odin_api.Doer) odin_api.Doer {
+		return odin_api.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"id":"2jjj","name":"Cached"}`)),
+			}, nil
+		})
+	}))
+
+	token, err := client.GetOdinFunToken("2jjj")
+	if err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+	if token.Name != "Cached" {
+		t.Errorf("Name = %s, want Cached", token.Name)
+	}
+	if got := srv.Requests(); got != 0 {
+		t.Errorf("requests = %d, want 0", got)
+	}
+}
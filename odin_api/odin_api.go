@@ -4,10 +4,8 @@
 package odin_api
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"context"
 	"fmt"
-	"time"
 )
 
 // AuthRequest 身份验证请求结构
@@ -15,7 +13,7 @@ type AuthRequest struct {
 	PublicKey string `json:"publickey"`
 	Timestamp string `json:"timestamp"`
 	Signature string `json:"signature"`
-	Referrer  string `json:"referrer"`
+	Referrer  string `json:"referrer,omitempty"`
 }
 
 // Identity 身份验证和身份注册请求
@@ -28,46 +26,36 @@ type Identity interface {
 // 身份验证相关功能
 
 // AuthIdentity 身份验证和身份注册请求
-func AuthIdentity(identity Identity) (string, error) {
-	c := NewClient()
-	// 获取当前时间戳
-	now := time.Now().UnixMilli()
-	timestamp := fmt.Sprintf("%d", now)
-
-	// 签名时间戳
-	signature, err := identity.Sign([]byte(timestamp))
-	if err != nil {
-		return "", fmt.Errorf("签名生成失败: %w", err)
-	}
+// 可通过opts配置所使用的客户端，例如WithBaseURL或WithHTTPClient
+func AuthIdentity(identity Identity, opts ...Option) (string, error) {
+	return AuthIdentityCtx(context.Background(), identity, opts...)
+}
 
-	// 创建授权请求
-	authReq := AuthRequest{
-		PublicKey: base64.StdEncoding.EncodeToString(identity.GetPublicKey()),
-		Timestamp: timestamp,
-		Signature: base64.StdEncoding.EncodeToString(signature),
-		Referrer:  "zg8khi8rz0",
-	}
+// AuthIdentityCtx 与AuthIdentity相同，但使用ctx控制请求的取消和超时
+func AuthIdentityCtx(ctx context.Context, identity Identity, opts ...Option) (string, error) {
+	return NewClient(opts...).AuthIdentityCtx(ctx, identity)
+}
 
-	// 发送请求
-	resp, err := c.Post("/auth", authReq)
-	if err != nil {
-		return "", fmt.Errorf("%w", err)
-	}
-	// 解析响应
-	var authToken struct {
-		Token string `json:"token"`
-	}
-	if err := json.Unmarshal(resp, &authToken); err != nil {
-		return "", fmt.Errorf("解析授权令牌失败: %w", err)
-	}
+// AuthIdentity 使用当前客户端的配置进行身份验证和身份注册请求
+func (c *Client) AuthIdentity(identity Identity) (string, error) {
+	return c.AuthIdentityCtx(context.Background(), identity)
+}
 
-	return authToken.Token, nil
+// AuthIdentityCtx 与AuthIdentity相同，但使用ctx控制请求的取消和超时
+// 使用DefaultReferrer作为推荐码，需要自定义推荐码时请使用Authenticate
+func (c *Client) AuthIdentityCtx(ctx context.Context, identity Identity) (string, error) {
+	return c.Authenticate(ctx, identity, AuthOptions{Referrer: DefaultReferrer})
 }
 
 // 用户相关功能
 
 // ChangeUsername 使用授权令牌更改用户名
 func (c *Client) ChangeUsername(username, principalID, authToken string) (*OdinUser, error) {
+	return c.ChangeUsernameCtx(context.Background(), username, principalID, authToken)
+}
+
+// ChangeUsernameCtx 与ChangeUsername相同，但使用ctx控制请求的取消和超时
+func (c *Client) ChangeUsernameCtx(ctx context.Context, username, principalID, authToken string) (*OdinUser, error) {
 	// 创建表单数据
 	formData := map[string]string{
 		"username": username,
@@ -75,14 +63,14 @@ func (c *Client) ChangeUsername(username, principalID, authToken string) (*OdinU
 
 	// 发送请求
 	endpoint := fmt.Sprintf("/user/profile?user=%s", principalID)
-	resp, err := c.PostMultipart(endpoint, formData)
+	resp, err := c.PostMultipartCtx(ctx, endpoint, formData)
 	if err != nil {
 		return nil, fmt.Errorf("更改用户名请求失败: %w", err)
 	}
 
 	var odinUser *OdinUser
 
-	if err := json.Unmarshal(resp, &odinUser); err != nil {
+	if err := c.decode(endpoint, resp, &odinUser); err != nil {
 		return nil, fmt.Errorf("解析失败: %w", err)
 	}
 
@@ -91,35 +79,58 @@ func (c *Client) ChangeUsername(username, principalID, authToken string) (*OdinU
 
 // GetOdinFunUser 获取Odin.fun用户信息
 func (c *Client) GetOdinFunUser(principalID string) (*OdinUser, error) {
+	return c.GetOdinFunUserCtx(context.Background(), principalID)
+}
+
+// GetOdinFunUserCtx 与GetOdinFunUser相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetOdinFunUserCtx(ctx context.Context, principalID string) (*OdinUser, error) {
 	// 发送请求
 	endpoint := fmt.Sprintf("/user/%s", principalID)
-	resp, err := c.Get(endpoint)
+	resp, err := c.GetCtx(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户信息失败: %w", err)
 	}
-	fmt.Println(string(resp))
+
 	// 解析响应
 	var odinUser *OdinUser
-
-	if err := json.Unmarshal(resp, &odinUser); err != nil {
+	if err := c.decode(endpoint, resp, &odinUser); err != nil {
 		return nil, fmt.Errorf("解析用户信息失败: %w", err)
 	}
-	fmt.Println(odinUser)
+
 	return odinUser, nil
 }
 
 // GetUserBalances 获取用户余额列表
 func (c *Client) GetUserBalances(principalID string) (*OdinUserBalance, error) {
+	return c.GetUserBalancesCtx(context.Background(), principalID)
+}
+
+// GetUserBalancesCtx 与GetUserBalances相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetUserBalancesCtx(ctx context.Context, principalID string) (*OdinUserBalance, error) {
+	return c.getUserBalances(ctx, fmt.Sprintf("/user/%s/balances", principalID))
+}
+
+// GetUserBalancesPage 分页获取用户余额列表
+func (c *Client) GetUserBalancesPage(principalID string, page, limit int) (*OdinUserBalance, error) {
+	return c.GetUserBalancesPageCtx(context.Background(), principalID, page, limit)
+}
+
+// GetUserBalancesPageCtx 与GetUserBalancesPage相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetUserBalancesPageCtx(ctx context.Context, principalID string, page, limit int) (*OdinUserBalance, error) {
+	return c.getUserBalances(ctx, fmt.Sprintf("/user/%s/balances?page=%d&limit=%d", principalID, page, limit))
+}
+
+// getUserBalances 请求余额端点并解析响应
+func (c *Client) getUserBalances(ctx context.Context, endpoint string) (*OdinUserBalance, error) {
 	// 发送请求
-	endpoint := fmt.Sprintf("/user/%s/balances", principalID)
-	resp, err := c.Get(endpoint)
+	resp, err := c.GetCtx(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("获取用户余额失败: %w", err)
 	}
 
 	// 解析响应
 	var balances OdinUserBalance
-	if err := json.Unmarshal(resp, &balances); err != nil {
+	if err := c.decode(endpoint, resp, &balances); err != nil {
 		return nil, fmt.Errorf("解析用户余额失败: %w", err)
 	}
 
@@ -128,7 +139,12 @@ func (c *Client) GetUserBalances(principalID string) (*OdinUserBalance, error) {
 
 // GetUserTokenBalance 获取用户特定代币余额
 func (c *Client) GetUserTokenBalance(principalID, tokenID string) (*BalanceDetail, error) {
-	balances, err := c.GetUserBalances(principalID)
+	return c.GetUserTokenBalanceCtx(context.Background(), principalID, tokenID)
+}
+
+// GetUserTokenBalanceCtx 与GetUserTokenBalance相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetUserTokenBalanceCtx(ctx context.Context, principalID, tokenID string) (*BalanceDetail, error) {
+	balances, err := c.GetUserBalancesCtx(ctx, principalID)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +156,7 @@ func (c *Client) GetUserTokenBalance(principalID, tokenID string) (*BalanceDetai
 		}
 	}
 
-	return nil, fmt.Errorf("未找到代币ID %s 的余额", tokenID)
+	return nil, fmt.Errorf("未找到代币ID %s 的余额: %w", tokenID, ErrNotFound)
 }
 
 // 代币相关功能
@@ -152,6 +168,11 @@ type CommentRequest struct {
 
 // PostComment 发表评论
 func (c *Client) PostComment(commentMessage, principalID, tokenID string) (string, error) {
+	return c.PostCommentCtx(context.Background(), commentMessage, principalID, tokenID)
+}
+
+// PostCommentCtx 与PostComment相同，但使用ctx控制请求的取消和超时
+func (c *Client) PostCommentCtx(ctx context.Context, commentMessage, principalID, tokenID string) (string, error) {
 	// 创建评论请求
 	commentReq := CommentRequest{
 		Message: commentMessage,
@@ -159,7 +180,7 @@ func (c *Client) PostComment(commentMessage, principalID, tokenID string) (strin
 
 	// 发送请求
 	endpoint := fmt.Sprintf("/token/%s/comment?user=%s", tokenID, principalID)
-	resp, err := c.Post(endpoint, commentReq)
+	resp, err := c.PostCtx(ctx, endpoint, commentReq)
 	if err != nil {
 		return "", fmt.Errorf("发表评论请求失败: %w", err)
 	}
@@ -169,52 +190,71 @@ func (c *Client) PostComment(commentMessage, principalID, tokenID string) (strin
 
 // GetOdinFunTokens 获取最近交易的Odin.fun代币
 func (c *Client) GetOdinFunTokens() (*OdinFunTokens, error) {
-	// 发送请求
-	endpoint := "/tokens?sort=last_action_time%3Adesc&page=1&limit=100"
-	resp, err := c.Get(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("获取代币列表失败: %w", err)
-	}
+	return c.GetOdinFunTokensCtx(context.Background())
+}
 
-	// 解析响应
-	var tokens OdinFunTokens
-	if err := json.Unmarshal(resp, &tokens); err != nil {
-		return nil, fmt.Errorf("解析代币列表失败: %w", err)
-	}
+// GetOdinFunTokensCtx 与GetOdinFunTokens相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetOdinFunTokensCtx(ctx context.Context) (*OdinFunTokens, error) {
+	return c.GetOdinFunTokensPageCtx(ctx, 1, 100)
+}
+
+// GetOdinFunTokensPage 按最近交易时间分页获取Odin.fun代币
+func (c *Client) GetOdinFunTokensPage(page, limit int) (*OdinFunTokens, error) {
+	return c.GetOdinFunTokensPageCtx(context.Background(), page, limit)
+}
 
-	return &tokens, nil
+// GetOdinFunTokensPageCtx 与GetOdinFunTokensPage相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetOdinFunTokensPageCtx(ctx context.Context, page, limit int) (*OdinFunTokens, error) {
+	return c.GetTokensCtx(ctx, TokenQuery{
+		Sort:      SortByLastActionTime,
+		Direction: SortDesc,
+		Page:      page,
+		Limit:     limit,
+	})
 }
 
 // GetTokensByHighestMarketcap 获取市值最高的Odin.fun代币
 func (c *Client) GetTokensByHighestMarketcap() (*OdinFunTokens, error) {
-	// 发送请求
-	endpoint := "/tokens?sort=marketcap%3Adesc&page=1&limit=25"
-	resp, err := c.Get(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("获取代币列表失败: %w", err)
-	}
-
-	// 解析响应
-	var tokens OdinFunTokens
-	if err := json.Unmarshal(resp, &tokens); err != nil {
-		return nil, fmt.Errorf("解析代币列表失败: %w", err)
-	}
+	return c.GetTokensByHighestMarketcapCtx(context.Background())
+}
 
-	return &tokens, nil
+// GetTokensByHighestMarketcapCtx 与GetTokensByHighestMarketcap相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetTokensByHighestMarketcapCtx(ctx context.Context) (*OdinFunTokens, error) {
+	return c.GetTokensCtx(ctx, TokenQuery{
+		Sort:      SortByMarketcap,
+		Direction: SortDesc,
+		Page:      1,
+		Limit:     25,
+	})
 }
 
 // GetHolders 获取代币持有者
 func (c *Client) GetHolders(id string) (*Holders, error) {
+	return c.GetHoldersCtx(context.Background(), id)
+}
+
+// GetHoldersCtx 与GetHolders相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetHoldersCtx(ctx context.Context, id string) (*Holders, error) {
+	return c.GetHoldersPageCtx(ctx, id, 1, 10)
+}
+
+// GetHoldersPage 分页获取代币持有者
+func (c *Client) GetHoldersPage(id string, page, limit int) (*Holders, error) {
+	return c.GetHoldersPageCtx(context.Background(), id, page, limit)
+}
+
+// GetHoldersPageCtx 与GetHoldersPage相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetHoldersPageCtx(ctx context.Context, id string, page, limit int) (*Holders, error) {
 	// 发送请求
-	endpoint := fmt.Sprintf("/token/%s/owners?page=1&limit=10", id)
-	resp, err := c.Get(endpoint)
+	endpoint := fmt.Sprintf("/token/%s/owners?page=%d&limit=%d", id, page, limit)
+	resp, err := c.GetCtx(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("获取持有者列表失败: %w", err)
 	}
 
 	// 解析响应
 	var holders Holders
-	if err := json.Unmarshal(resp, &holders); err != nil {
+	if err := c.decode(endpoint, resp, &holders); err != nil {
 		return nil, fmt.Errorf("解析持有者列表失败: %w", err)
 	}
 
@@ -223,9 +263,14 @@ func (c *Client) GetHolders(id string) (*Holders, error) {
 
 // GetOdinFunToken 获取特定的Odin.fun代币
 func (c *Client) GetOdinFunToken(id string) (*TokenDetail, error) {
+	return c.GetOdinFunTokenCtx(context.Background(), id)
+}
+
+// GetOdinFunTokenCtx 与GetOdinFunToken相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetOdinFunTokenCtx(ctx context.Context, id string) (*TokenDetail, error) {
 	// 发送请求
 	endpoint := fmt.Sprintf("/token/%s", id)
-	resp, err := c.Get(endpoint)
+	resp, err := c.GetCtx(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("获取代币信息失败: %w", err)
 	}
@@ -233,7 +278,7 @@ func (c *Client) GetOdinFunToken(id string) (*TokenDetail, error) {
 	// 解析响应
 	var tokenResponse TokenDetail
 
-	if err := json.Unmarshal(resp, &tokenResponse); err != nil {
+	if err := c.decode(endpoint, resp, &tokenResponse); err != nil {
 		return nil, fmt.Errorf("解析代币信息失败: %w", err)
 	}
 
@@ -247,16 +292,31 @@ type TokenTarget struct {
 
 // GetOdinFunTrades 获取特定代币的交易历史
 func (c *Client) GetOdinFunTrades(target TokenTarget) (*TokenTraders, error) {
+	return c.GetOdinFunTradesCtx(context.Background(), target)
+}
+
+// GetOdinFunTradesCtx 与GetOdinFunTrades相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetOdinFunTradesCtx(ctx context.Context, target TokenTarget) (*TokenTraders, error) {
+	return c.GetOdinFunTradesPageCtx(ctx, target, 1, 9999)
+}
+
+// GetOdinFunTradesPage 分页获取特定代币的交易历史
+func (c *Client) GetOdinFunTradesPage(target TokenTarget, page, limit int) (*TokenTraders, error) {
+	return c.GetOdinFunTradesPageCtx(context.Background(), target, page, limit)
+}
+
+// GetOdinFunTradesPageCtx 与GetOdinFunTradesPage相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetOdinFunTradesPageCtx(ctx context.Context, target TokenTarget, page, limit int) (*TokenTraders, error) {
 	// 发送请求
-	endpoint := fmt.Sprintf("/token/%s/trades?page=1&limit=9999&time_min=%d", target.Id, target.LastActionTimestamp)
-	resp, err := c.Get(endpoint)
+	endpoint := fmt.Sprintf("/token/%s/trades?page=%d&limit=%d&time_min=%d", target.Id, page, limit, target.LastActionTimestamp)
+	resp, err := c.GetCtx(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("获取代币交易历史失败: %w", err)
 	}
 
 	// 解析响应
 	var trades TokenTraders
-	if err := json.Unmarshal(resp, &trades); err != nil {
+	if err := c.decode(endpoint, resp, &trades); err != nil {
 		return nil, fmt.Errorf("解析代币交易历史失败: %w", err)
 	}
 
@@ -267,16 +327,21 @@ func (c *Client) GetOdinFunTrades(target TokenTarget) (*TokenTraders, error) {
 
 // GetBTCPrice 获取比特币当前价格信息
 func (c *Client) GetBTCPrice() (*BTCInfo, error) {
+	return c.GetBTCPriceCtx(context.Background())
+}
+
+// GetBTCPriceCtx 与GetBTCPrice相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetBTCPriceCtx(ctx context.Context) (*BTCInfo, error) {
 	// 发送请求
 	endpoint := "/currency/btc"
-	resp, err := c.Get(endpoint)
+	resp, err := c.GetCtx(ctx, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("获取比特币价格失败: %w", err)
 	}
 
 	// 解析响应
 	var btcInfo BTCInfo
-	if err := json.Unmarshal(resp, &btcInfo); err != nil {
+	if err := c.decode(endpoint, resp, &btcInfo); err != nil {
 		return nil, fmt.Errorf("解析比特币价格信息失败: %w", err)
 	}
 
@@ -0,0 +1,276 @@
+package odintest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/MrHat365/odin-go/internal/websocket"
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/feed"
+)
+
+// FeedServer 模拟实时推送服务的WebSocket服务器，可并发使用
+//
+// FeedServer实现feed包假定的协议：客户端发送 {"action":"subscribe","channel":"...","token":"..."}
+// 订阅或取消订阅，服务器推送 {"type":"...","channel":"...","token":"...","data":{...}}。
+// 只有订阅了对应频道（以及代币，或订阅了整个频道）的连接会收到事件。
+type FeedServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	conns       map[*feedConn]struct{}
+	connections int
+	changed     chan struct{} // 连接或订阅变化时关闭并替换
+}
+
+// feedConn 服务器端的一个WebSocket连接
+type feedConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+
+	writeMu sync.Mutex
+	subs    map[feed.Subscription]struct{} // 由FeedServer.mu保护
+}
+
+// NewFeedServer 启动推送服务器，使用完毕后调用Close
+func NewFeedServer() *FeedServer {
+	s := &FeedServer{
+		conns:   make(map[*feedConn]struct{}),
+		changed: make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// WebSocketURL 返回ws://形式的地址，可传给feed.Config.URL
+func (s *FeedServer) WebSocketURL() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+// Connections 返回累计接受的连接数，可用于检查重连行为
+func (s *FeedServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.connections
+}
+
+// Subscriptions 返回当前所有连接的订阅，已去重
+func (s *FeedServer) Subscriptions() []feed.Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var subs []feed.Subscription
+	for c := range s.conns {
+		for sub := range c.subs {
+			if !slices.Contains(subs, sub) {
+				subs = append(subs, sub)
+			}
+		}
+	}
+	return subs
+}
+
+// WaitSubscribed 等待直到有连接订阅了sub，ctx被取消或超时时返回ctx.Err()
+// 推送事件前调用，避免事件在订阅生效之前被丢弃
+func (s *FeedServer) WaitSubscribed(ctx context.Context, sub feed.Subscription) error {
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		subscribed := false
+		for c := range s.conns {
+			if _, ok := c.subs[sub]; ok {
+				subscribed = true
+				break
+			}
+		}
+		s.mu.Unlock()
+
+		if subscribed {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Publish 向订阅了channel和tokenID的连接推送事件，返回收到事件的连接数
+func (s *FeedServer) Publish(typ feed.EventType, channel feed.Channel, tokenID string, data any) (int, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return 0, fmt.Errorf("编码事件失败: %w", err)
+	}
+	message, err := json.Marshal(map[string]any{
+		"type":    typ,
+		"channel": channel,
+		"token":   tokenID,
+		"data":    json.RawMessage(payload),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("编码事件失败: %w", err)
+	}
+
+	s.mu.Lock()
+	var targets []*feedConn
+	for c := range s.conns {
+		_, all := c.subs[feed.Subscription{Channel: channel}]
+		_, one := c.subs[feed.Subscription{Channel: channel, TokenID: tokenID}]
+		if all || one {
+			targets = append(targets, c)
+		}
+	}
+	s.mu.Unlock()
+
+	sent := 0
+	for _, c := range targets {
+		if c.writeFrame(websocket.OpText, message) == nil {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// PublishTrade 在trades频道推送成交事件
+func (s *FeedServer) PublishTrade(trade odin_api.Trade) (int, error) {
+	return s.Publish(feed.EventTrade, feed.ChannelTrades, trade.Token, trade)
+}
+
+// PublishToken 在tokens频道推送新代币事件
+func (s *FeedServer) PublishToken(token odin_api.TokenDetail) (int, error) {
+	return s.Publish(feed.EventNewToken, feed.ChannelTokens, token.ID, token)
+}
+
+// PublishPrice 在prices频道推送价格更新事件
+func (s *FeedServer) PublishPrice(token odin_api.TokenDetail) (int, error) {
+	return s.Publish(feed.EventPrice, feed.ChannelPrices, token.ID, token)
+}
+
+// DropConnections 关闭所有连接，用于测试断线重连和重新订阅
+func (s *FeedServer) DropConnections() {
+	s.mu.Lock()
+	conns := make([]*feedConn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.conn.Close()
+	}
+}
+
+// Close 关闭所有连接并停止服务器
+func (s *FeedServer) Close() {
+	s.DropConnections()
+	s.Server.Close()
+}
+
+// notifyLocked 唤醒等待订阅变化的调用方，调用方需持有锁
+func (s *FeedServer) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// handle 完成WebSocket握手并处理客户端消息
+func (s *FeedServer) handle(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(w, "需要WebSocket升级请求", http.StatusBadRequest)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "不支持连接劫持", http.StatusInternalServerError)
+		return
+	}
+	conn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", websocket.AcceptKey(key))
+	if err := brw.Flush(); err != nil {
+		return
+	}
+
+	c := &feedConn{conn: conn, br: brw.Reader, subs: make(map[feed.Subscription]struct{})}
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.connections++
+	s.notifyLocked()
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.notifyLocked()
+		s.mu.Unlock()
+	}()
+
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case websocket.OpPing:
+			c.writeFrame(websocket.OpPong, payload)
+		case websocket.OpClose:
+			c.writeFrame(websocket.OpClose, payload)
+			return
+		case websocket.OpText:
+			var req struct {
+				Action string `json:"action"`
+				feed.Subscription
+			}
+			if json.Unmarshal(payload, &req) != nil {
+				continue
+			}
+			s.mu.Lock()
+			switch req.Action {
+			case "subscribe":
+				c.subs[req.Subscription] = struct{}{}
+			case "unsubscribe":
+				delete(c.subs, req.Subscription)
+			}
+			s.notifyLocked()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// maxFrameSize 服务器接受的最大帧
+const maxFrameSize = 1 << 20
+
+// readFrame 读取客户端发送的一个帧，FeedServer不支持分片消息
+func (c *feedConn) readFrame() (opcode byte, payload []byte, err error) {
+	frame, err := websocket.ReadFrame(c.br, maxFrameSize)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !frame.Masked {
+		return 0, nil, errors.New("客户端帧未加掩码")
+	}
+	return frame.Opcode, frame.Payload, nil
+}
+
+// writeFrame 发送一个不加掩码的完整帧
+func (c *feedConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return websocket.WriteFrame(c.conn, opcode, payload, false)
+}
@@ -0,0 +1,352 @@
+package odintest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNoInteraction 严格回放模式下请求在录像中没有匹配项
+var ErrNoInteraction = errors.New("录像中没有匹配的请求")
+
+// Mode 录制器的工作模式
+type Mode int
+
+const (
+	// ModeReplay 从录像文件回放响应
+	ModeReplay Mode = iota
+	// ModeRecord 发送真实请求并录制到录像文件
+	ModeRecord
+)
+
+// RecordedRequest 录像中保存的请求
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse 录像中保存的响应
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction 一次请求和响应
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Cassette 录像文件的内容
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette 从文件读取录像
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取录像失败: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("解析录像失败: %w", err)
+	}
+	return &cassette, nil
+}
+
+// Save 将录像写入文件，文件权限为0600
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("编码录像失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("创建录像目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("写入录像失败: %w", err)
+	}
+	return nil
+}
+
+// RecorderConfig Recorder的配置
+type RecorderConfig struct {
+	Mode Mode
+	// Strict 回放模式下遇到未录制的请求时返回ErrNoInteraction，
+	// 否则将其转发给Transport
+	Strict bool
+	// Transport 实际发送请求的RoundTripper，默认http.DefaultTransport
+	Transport http.RoundTripper
+	// ScrubHeaders 录制时额外移除的请求头和响应头，scrubbedHeaders中的请求头总是会被移除
+	ScrubHeaders []string
+	// ScrubFields 录制时额外替换为[REDACTED]的JSON字段名（不区分大小写），在任意嵌套层级生效；
+	// scrubbedFields中的字段总是会被替换
+	ScrubFields []string
+	// Scrub 在默认清理之后、保存交互之前调用，可用于清理其他敏感内容
+	Scrub func(*Interaction)
+}
+
+// redacted 录制时替换敏感JSON字段的值
+const redacted = "[REDACTED]"
+
+// scrubbedHeaders 录制时总是从请求和响应中移除的头
+var scrubbedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// scrubbedFields 录制时总是被替换的JSON字段，例如 /auth 响应中的令牌
+// 只匹配顶层字段，因为Trade和Holder等嵌套对象中的token字段是代币ID而不是凭据
+var scrubbedFields = []string{"token", "access_token", "refresh_token"}
+
+// Recorder 录制和回放HTTP交互的http.RoundTripper
+//
+// 回放时按方法、路径和查询参数匹配请求，相同请求的多次录制按顺序依次返回，
+// 用尽后重复返回最后一次的响应。
+type Recorder struct {
+	path string
+	cfg  RecorderConfig
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     map[int]bool
+}
+
+// NewRecorder 创建录制器，回放模式下会立即读取path指定的录像文件
+func NewRecorder(path string, cfg RecorderConfig) (*Recorder, error) {
+	if cfg.Transport == nil {
+		cfg.Transport = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		path:     path,
+		cfg:      cfg,
+		cassette: &Cassette{},
+		used:     make(map[int]bool),
+	}
+	if cfg.Mode == ModeReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+	}
+	return r, nil
+}
+
+// Client 返回使用该录制器的http.Client，可传给odin_api.WithHTTPClient
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Cassette 返回当前录像的副本
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save 录制模式下将录像写入文件，回放模式下不做任何操作
+func (r *Recorder) Save() error {
+	if r.cfg.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.Save(r.path)
+}
+
+// RoundTrip 实现http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.cfg.Mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// record 发送请求并保存请求和响应
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("读取请求体失败: %w", err)
+		}
+		req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.cfg.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.scrubHeader(req.Header),
+			Body:   r.scrubBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.scrubHeader(resp.Header),
+			Body:       r.scrubBody(respBody),
+		},
+	}
+	// 清理后的响应体长度可能变化，回放时根据响应体重新计算
+	interaction.Response.Header.Del("Content-Length")
+	if r.cfg.Scrub != nil {
+		r.cfg.Scrub(&interaction)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// scrubHeader 返回移除了敏感头的副本
+func (r *Recorder) scrubHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range scrubbedHeaders {
+		header.Del(name)
+	}
+	for _, name := range r.cfg.ScrubHeaders {
+		header.Del(name)
+	}
+	return header
+}
+
+// scrubBody 将JSON中敏感字段的值替换为[REDACTED]，非JSON或没有敏感字段时原样返回
+func (r *Recorder) scrubBody(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || !r.scrubValue(v, true) {
+		return string(body)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+// scrubValue 递归替换敏感字段，top表示v是否为JSON文档的顶层对象，返回是否有字段被替换
+func (r *Recorder) scrubValue(v any, top bool) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if r.sensitiveField(key, top) {
+				v[key] = redacted
+				changed = true
+				continue
+			}
+			changed = r.scrubValue(value, false) || changed
+		}
+	case []any:
+		for _, value := range v {
+			changed = r.scrubValue(value, false) || changed
+		}
+	}
+	return changed
+}
+
+// sensitiveField 判断JSON字段是否需要替换，scrubbedFields只在顶层对象中匹配
+func (r *Recorder) sensitiveField(key string, top bool) bool {
+	if top {
+		for _, name := range scrubbedFields {
+			if strings.EqualFold(key, name) {
+				return true
+			}
+		}
+	}
+	for _, name := range r.cfg.ScrubFields {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// replay 返回录像中匹配的响应
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	recorded, ok := r.match(req)
+	if !ok {
+		if r.cfg.Strict {
+			return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+		}
+		return r.cfg.Transport.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(recorded.Body))),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// match 查找第一个未使用的匹配项，都已使用时返回最后一个匹配项
+func (r *Recorder) match(req *http.Request) (RecordedResponse, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !sameRequest(interaction.Request, req) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return interaction.Response, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return RecordedResponse{}, false
+	}
+	return r.cassette.Interactions[last].Response, true
+}
+
+// sameRequest 比较方法、路径和查询参数，查询参数的顺序不影响匹配
+func sameRequest(recorded RecordedRequest, req *http.Request) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+	u, err := req.URL.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	return u.Path == req.URL.Path && u.Query().Encode() == req.URL.Query().Encode()
+}
@@ -0,0 +1,169 @@
+package odintest_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	srv := odintest.NewServer(odintest.Config{})
+	srv.AddToken(odin_api.TokenDetail{ID: "2jjj", Name: "ODIN"})
+	baseURL := srv.BaseURL()
+	path := filepath.Join(t.TempDir(), "cassettes", "odin.json")
+
+	recorder, err := odintest.NewRecorder(path, odintest.RecorderConfig{Mode: odintest.ModeRecord, ScrubHeaders: []string{"X-Api-Key"}})
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	client := odin_api.NewClient(
+		odin_api.WithBaseURL(baseURL),
+		odin_api.WithHTTPClient(recorder.Client()),
+		odin_api.WithHeader("X-Api-Key", "secret-key"),
+	)
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := client.AuthIdentity(identity)
+	if err != nil {
+		t.Fatalf("AuthIdentity: %v", err)
+	}
+	client.Token = token
+	if _, err := client.PostComment("gm", identity.PrincipalText(), "2jjj"); err != nil {
+		t.Fatalf("PostComment: %v", err)
+	}
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	srv.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("cassette mode = %o, want 600", mode)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{token, "secret-key", "Bearer"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+	if !strings.Contains(string(data), "[REDACTED]") {
+		t.Error("cassette does not contain the redacted token")
+	}
+
+	// 服务器已关闭，严格回放只能使用录像中的响应
+	replayer, err := odintest.NewRecorder(path, odintest.RecorderConfig{Mode: odintest.ModeReplay, Strict: true})
+	if err != nil {
+		t.Fatalf("NewRecorder replay: %v", err)
+	}
+	if n := len(replayer.Cassette().Interactions); n != 3 {
+		t.Fatalf("interactions = %d, want 3", n)
+	}
+	replay := odin_api.NewClient(odin_api.WithBaseURL(baseURL), odin_api.WithHTTPClient(replayer.Client()))
+
+	detail, err := replay.GetOdinFunToken("2jjj")
+	if err != nil {
+		t.Fatalf("replayed GetOdinFunToken: %v", err)
+	}
+	if detail.Name != "ODIN" {
+		t.Errorf("Name = %q, want ODIN", detail.Name)
+	}
+	if _, err := replay.GetOdinFunToken("other"); !errors.Is(err, odintest.ErrNoInteraction) {
+		t.Errorf("unrecorded request err = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestRecorderCustomScrub(t *testing.T) {
+	srv := odintest.NewServer(odintest.Config{})
+	defer srv.Close()
+	srv.AddUser(odin_api.OdinUser{Principal: "user-1", Username: "alice"})
+	path := filepath.Join(t.TempDir(), "odin.json")
+
+	recorder, err := odintest.NewRecorder(path, odintest.RecorderConfig{
+		Mode:        odintest.ModeRecord,
+		ScrubFields: []string{"Username"},
+		Scrub: func(i *odintest.Interaction) {
+			i.Request.URL = strings.ReplaceAll(i.Request.URL, "user-1", "user-x")
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	client := srv.NewClient(odin_api.WithHTTPClient(recorder.Client()))
+	if _, err := client.GetOdinFunUser("user-1"); err != nil {
+		t.Fatalf("GetOdinFunUser: %v", err)
+	}
+
+	interactions := recorder.Cassette().Interactions
+	if len(interactions) != 1 {
+		t.Fatalf("interactions = %d, want 1", len(interactions))
+	}
+	got := interactions[0]
+	if strings.Contains(got.Request.URL, "user-1") || !strings.Contains(got.Request.URL, "user-x") {
+		t.Errorf("URL = %s, want user-1 replaced", got.Request.URL)
+	}
+	if strings.Contains(got.Response.Body, "alice") {
+		t.Errorf("response body = %s, want username redacted", got.Response.Body)
+	}
+}
+
+func TestRecorderKeepsNestedTokenIDs(t *testing.T) {
+	srv := newServer(t)
+	srv.AddToken(odin_api.TokenDetail{ID: "2jjj", Name: "ODIN"})
+	srv.AddTrades("2jjj", odin_api.Trade{ID: "t1", User: "user-1", Time: time.Now()})
+	srv.SetHolders("2jjj", []odin_api.Holder{{User: "user-1", Token: "2jjj"}})
+	path := filepath.Join(t.TempDir(), "odin.json")
+
+	recorder, err := odintest.NewRecorder(path, odintest.RecorderConfig{Mode: odintest.ModeRecord})
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	client := srv.NewClient(odin_api.WithHTTPClient(recorder.Client()))
+	if _, err := client.GetOdinFunTrades(odin_api.TokenTarget{Id: "2jjj"}); err != nil {
+		t.Fatalf("GetOdinFunTrades: %v", err)
+	}
+	if _, err := client.GetHolders("2jjj"); err != nil {
+		t.Fatalf("GetHolders: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// 交易和持有者中的token是代币ID，回放时必须保持原值
+	replayer, err := odintest.NewRecorder(path, odintest.RecorderConfig{Mode: odintest.ModeReplay, Strict: true})
+	if err != nil {
+		t.Fatalf("NewRecorder replay: %v", err)
+	}
+	replay := srv.NewClient(odin_api.WithHTTPClient(replayer.Client()))
+
+	trades, err := replay.GetOdinFunTrades(odin_api.TokenTarget{Id: "2jjj"})
+	if err != nil {
+		t.Fatalf("replayed GetOdinFunTrades: %v", err)
+	}
+	if len(trades.Data) != 1 || trades.Data[0].Token != "2jjj" {
+		t.Errorf("trades = %+v, want token 2jjj", trades.Data)
+	}
+	holders, err := replay.GetHolders("2jjj")
+	if err != nil {
+		t.Fatalf("replayed GetHolders: %v", err)
+	}
+	if len(holders.Data) != 1 || holders.Data[0].Token != "2jjj" {
+		t.Errorf("holders = %+v, want token 2jjj", holders.Data)
+	}
+}
@@ -0,0 +1,648 @@
+// Package odintest 提供进程内的Odin.fun REST API模拟服务器，用于离线测试
+//
+// Server基于httptest实现了认证、用户、余额、代币、持有者、交易、评论和BTC价格端点，
+// 支持预置数据以及延迟、限流和服务器错误等故障注入。GET响应带有ETag，可用于测试缓存的条件请求。
+package odintest
+
+import (
+	"cmp"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/aviate-labs/agent-go/principal"
+)
+
+// Config Server的配置
+type Config struct {
+	// TokenTTL 签发令牌的有效期，默认1小时
+	TokenTTL time.Duration
+	// MaxClockSkew 认证请求时间戳允许的最大偏差，默认5分钟
+	MaxClockSkew time.Duration
+	// Now 服务器时间来源，默认time.Now
+	Now func() time.Time
+}
+
+// Fault 注入的故障，作用于路径以PathPrefix开头的请求
+type Fault struct {
+	PathPrefix string        // 相对于BaseURL的路径前缀，为空时匹配所有请求
+	Latency    time.Duration // 处理请求前的延迟
+	Status     int           // 非0时直接返回该状态码，例如429或500
+	RetryAfter time.Duration // Status为429时附带的Retry-After
+	Times      int           // 生效次数，0表示一直生效
+}
+
+// Comment 通过API发表的评论
+type Comment struct {
+	TokenID string
+	User    string
+	Message string
+	Time    time.Time
+}
+
+// Server 模拟的Odin.fun API服务器，可并发使用
+type Server struct {
+	*httptest.Server
+	cfg Config
+
+	mu       sync.Mutex
+	users    map[string]*odin_api.OdinUser
+	balances map[string][]odin_api.BalanceDetail
+	tokens   map[string]*odin_api.TokenDetail
+	holders  map[string][]odin_api.Holder
+	trades   map[string][]odin_api.Trade
+	comments []Comment
+	btc      odin_api.BTCInfo
+	sessions map[string]session
+	faults   []*Fault
+	requests int
+}
+
+// session 已签发的令牌
+type session struct {
+	principal string
+	expiry    time.Time
+}
+
+// NewServer 启动一个模拟服务器，使用完毕后调用Close
+func NewServer(cfg Config) *Server {
+	if cfg.TokenTTL <= 0 {
+		cfg.TokenTTL = time.Hour
+	}
+	if cfg.MaxClockSkew <= 0 {
+		cfg.MaxClockSkew = 5 * time.Minute
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	s := &Server{
+		cfg:      cfg,
+		users:    make(map[string]*odin_api.OdinUser),
+		balances: make(map[string][]odin_api.BalanceDetail),
+		tokens:   make(map[string]*odin_api.TokenDetail),
+		holders:  make(map[string][]odin_api.Holder),
+		trades:   make(map[string][]odin_api.Trade),
+		sessions: make(map[string]session),
+		btc:      odin_api.BTCInfo{ID: 1, Symbol: "BTC", Datetime: cfg.Now().UTC(), Amount: 60000},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/auth", s.handleAuth)
+	mux.HandleFunc("POST /v1/user/profile", s.requireAuth(s.handleProfile))
+	mux.HandleFunc("GET /v1/user/{id}", s.handleUser)
+	mux.HandleFunc("GET /v1/user/{id}/balances", s.handleBalances)
+	mux.HandleFunc("GET /v1/tokens", s.handleTokens)
+	mux.HandleFunc("GET /v1/token/{id}", s.handleToken)
+	mux.HandleFunc("GET /v1/token/{id}/owners", s.handleOwners)
+	mux.HandleFunc("GET /v1/token/{id}/trades", s.handleTrades)
+	mux.HandleFunc("POST /v1/token/{id}/comment", s.requireAuth(s.handleComment))
+	mux.HandleFunc("GET /v1/currency/btc", s.handleBTC)
+
+	s.Server = httptest.NewServer(s.withFaults(withETag(mux)))
+	return s
+}
+
+// BaseURL 返回可传给odin_api.WithBaseURL的地址
+func (s *Server) BaseURL() string {
+	return s.URL + "/v1"
+}
+
+// NewClient 创建指向该服务器的odin_api客户端
+func (s *Server) NewClient(opts ...odin_api.Option) *odin_api.Client {
+	return odin_api.NewClient(append([]odin_api.Option{odin_api.WithBaseURL(s.BaseURL())}, opts...)...)
+}
+
+// AddUser 预置用户
+func (s *Server) AddUser(user odin_api.OdinUser) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[user.Principal] = &user
+}
+
+// SetBalances 预置用户的代币余额
+func (s *Server) SetBalances(principalID string, balances []odin_api.BalanceDetail) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.balances[principalID] = slices.Clone(balances)
+}
+
+// AddToken 预置代币
+func (s *Server) AddToken(token odin_api.TokenDetail) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[token.ID] = &token
+}
+
+// SetHolders 预置代币的持有者
+func (s *Server) SetHolders(tokenID string, holders []odin_api.Holder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.holders[tokenID] = slices.Clone(holders)
+}
+
+// AddTrades 追加代币的交易记录
+func (s *Server) AddTrades(tokenID string, trades ...odin_api.Trade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, trade := range trades {
+		if trade.Token == "" {
+			trade.Token = tokenID
+		}
+		s.trades[tokenID] = append(s.trades[tokenID], trade)
+	}
+}
+
+// SetBTCPrice 设置BTC价格
+func (s *Server) SetBTCPrice(info odin_api.BTCInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.btc = info
+}
+
+// Comments 返回所有已发表的评论
+func (s *Server) Comments() []Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.comments)
+}
+
+// User 返回用户的当前状态
+func (s *Server) User(principalID string) (odin_api.OdinUser, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[principalID]
+	if !ok {
+		return odin_api.OdinUser{}, false
+	}
+	return *user, true
+}
+
+// AddFault 注入故障，按添加顺序匹配第一个生效的故障
+func (s *Server) AddFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := fault
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults 移除所有故障
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// ExpireTokens 使所有已签发的令牌失效，用于测试重新认证
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = make(map[string]session)
+}
+
+// Requests 返回服务器收到的请求总数
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+// withFaults 在路由之前统计请求并应用故障
+func (s *Server) withFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/v1")
+
+		s.mu.Lock()
+		s.requests++
+		var fault Fault
+		for i, f := range s.faults {
+			if !strings.HasPrefix(path, f.PathPrefix) {
+				continue
+			}
+			fault = *f
+			if f.Times > 0 {
+				f.Times--
+				if f.Times == 0 {
+					s.faults = slices.Delete(s.faults, i, i+1)
+				}
+			}
+			break
+		}
+		s.mu.Unlock()
+
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.Status != 0 {
+			if fault.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(fault.RetryAfter.Round(time.Second)/time.Second)))
+			}
+			writeError(w, fault.Status, http.StatusText(fault.Status))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withETag 为成功的GET响应设置基于内容的ETag，If-None-Match匹配时返回304
+func withETag(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+		maps.Copy(w.Header(), rec.Header())
+		if rec.Code != http.StatusOK {
+			w.WriteHeader(rec.Code)
+			w.Write(rec.Body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(rec.Body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write(rec.Body.Bytes())
+	})
+}
+
+// requireAuth 校验Bearer令牌，并确认其与user查询参数一致
+func (s *Server) requireAuth(next func(w http.ResponseWriter, r *http.Request, principal string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		s.mu.Lock()
+		sess, ok := s.sessions[token]
+		s.mu.Unlock()
+
+		if !ok || s.cfg.Now().After(sess.expiry) {
+			writeError(w, http.StatusUnauthorized, "invalid or expired token")
+			return
+		}
+		if user := r.URL.Query().Get("user"); user != "" && user != sess.principal {
+			writeError(w, http.StatusForbidden, "user does not match token")
+			return
+		}
+		next(w, r, sess.principal)
+	}
+}
+
+// handleAuth 校验Ed25519签名并签发令牌，首次认证时注册用户
+func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
+	var req odin_api.AuthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	der, err := base64.StdEncoding.DecodeString(req.PublicKey)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid public key encoding")
+		return
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid public key")
+		return
+	}
+	edKey, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "unsupported key type")
+		return
+	}
+	sig, err := base64.StdEncoding.DecodeString(req.Signature)
+	if err != nil || !ed25519.Verify(edKey, []byte(req.Timestamp), sig) {
+		writeError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	now := s.cfg.Now()
+	w.Header().Set("Date", now.UTC().Format(http.TimeFormat))
+	ms, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid timestamp")
+		return
+	}
+	if skew := now.Sub(time.UnixMilli(ms)); skew > s.cfg.MaxClockSkew || skew < -s.cfg.MaxClockSkew {
+		writeError(w, http.StatusUnauthorized, "timestamp out of range")
+		return
+	}
+
+	id := principal.NewSelfAuthenticating(der).String()
+	expiry := now.Add(s.cfg.TokenTTL)
+	token := issueToken(id, now, expiry)
+
+	s.mu.Lock()
+	s.sessions[token] = session{principal: id, expiry: expiry}
+	if _, ok := s.users[id]; !ok {
+		user := &odin_api.OdinUser{
+			Principal:     id,
+			Username:      id,
+			RefCode:       id[:5],
+			AccessAllowed: true,
+			CreatedAt:     now.UTC(),
+		}
+		if req.Referrer != "" {
+			user.Referrer = odin_api.Some(req.Referrer)
+		}
+		s.users[id] = user
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]string{"token": token})
+}
+
+// handleProfile 修改用户名
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request, principalID string) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form")
+		return
+	}
+
+	s.mu.Lock()
+	user, ok := s.users[principalID]
+	if ok {
+		if username := r.FormValue("username"); username != "" {
+			user.Username = username
+		}
+	}
+	var out odin_api.OdinUser
+	if ok {
+		out = *user
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	writeJSON(w, out)
+}
+
+// handleUser 返回用户信息
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.User(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	writeJSON(w, user)
+}
+
+// handleBalances 分页返回用户余额
+func (s *Server) handleBalances(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	balances := slices.Clone(s.balances[r.PathValue("id")])
+	s.mu.Unlock()
+
+	page, limit := pagination(r, 100)
+	data, count := paginate(balances, page, limit)
+	writeJSON(w, odin_api.OdinUserBalance{Data: data, Page: odin_api.Int(page), Limit: odin_api.Int(limit), Count: odin_api.Int(count)})
+}
+
+// handleTokens 按排序、过滤和搜索条件分页返回代币
+func (s *Server) handleTokens(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	s.mu.Lock()
+	tokens := make([]odin_api.TokenDetail, 0, len(s.tokens))
+	for _, token := range s.tokens {
+		tokens = append(tokens, *token)
+	}
+	s.mu.Unlock()
+
+	tokens = slices.DeleteFunc(tokens, func(t odin_api.TokenDetail) bool {
+		if v := q.Get("bonded"); v != "" && strconv.FormatBool(t.Bonded) != v {
+			return true
+		}
+		if v := q.Get("featured"); v != "" && strconv.FormatBool(t.Featured) != v {
+			return true
+		}
+		if v := q.Get("trading"); v != "" && strconv.FormatBool(t.Trading) != v {
+			return true
+		}
+		if v := strings.ToLower(q.Get("search")); v != "" &&
+			!strings.Contains(strings.ToLower(t.Name), v) && !strings.Contains(strings.ToLower(t.Ticker), v) {
+			return true
+		}
+		return false
+	})
+	sortTokens(tokens, q.Get("sort"))
+
+	page, limit := pagination(r, 100)
+	data, count := paginate(tokens, page, limit)
+	writeJSON(w, odin_api.OdinFunTokens{Data: data, Page: odin_api.Int(page), Limit: odin_api.Int(limit), Count: odin_api.Int(count)})
+}
+
+// handleToken 返回代币详情
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	token, ok := s.tokens[r.PathValue("id")]
+	var out odin_api.TokenDetail
+	if ok {
+		out = *token
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "token not found")
+		return
+	}
+	writeJSON(w, out)
+}
+
+// handleOwners 分页返回代币持有者
+func (s *Server) handleOwners(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	holders := slices.Clone(s.holders[r.PathValue("id")])
+	s.mu.Unlock()
+
+	page, limit := pagination(r, 10)
+	data, count := paginate(holders, page, limit)
+	writeJSON(w, odin_api.Holders{Data: data, Page: odin_api.Int(page), Limit: odin_api.Int(limit), Count: odin_api.Int(count)})
+}
+
+// handleTrades 按时间倒序分页返回time_min之后的交易
+func (s *Server) handleTrades(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	trades := slices.Clone(s.trades[r.PathValue("id")])
+	s.mu.Unlock()
+
+	if v := r.URL.Query().Get("time_min"); v != "" {
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+			trades = slices.DeleteFunc(trades, func(t odin_api.Trade) bool {
+				return t.Time.UnixMilli() < ms
+			})
+		}
+	}
+	slices.SortStableFunc(trades, func(a, b odin_api.Trade) int {
+		return b.Time.Compare(a.Time)
+	})
+
+	page, limit := pagination(r, 100)
+	data, count := paginate(trades, page, limit)
+	writeJSON(w, odin_api.TokenTraders{Data: data, Page: odin_api.Int(page), Limit: odin_api.Int(limit), Count: odin_api.Int(count)})
+}
+
+// handleComment 记录评论
+func (s *Server) handleComment(w http.ResponseWriter, r *http.Request, principalID string) {
+	var req odin_api.CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Message == "" {
+		writeError(w, http.StatusBadRequest, "invalid comment")
+		return
+	}
+
+	tokenID := r.PathValue("id")
+	s.mu.Lock()
+	_, ok := s.tokens[tokenID]
+	if ok {
+		s.comments = append(s.comments, Comment{
+			TokenID: tokenID,
+			User:    principalID,
+			Message: req.Message,
+			Time:    s.cfg.Now().UTC(),
+		})
+		if token := s.tokens[tokenID]; token != nil {
+			token.CommentCount++
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "token not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, map[string]string{"message": req.Message})
+}
+
+// handleBTC 返回BTC价格
+func (s *Server) handleBTC(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	info := s.btc
+	s.mu.Unlock()
+
+	writeJSON(w, info)
+}
+
+// sortTokens 按 "字段:方向" 格式的排序参数排序
+func sortTokens(tokens []odin_api.TokenDetail, sortParam string) {
+	field, direction, _ := strings.Cut(sortParam, ":")
+	desc := direction != "asc"
+
+	compare := func(a, b odin_api.TokenDetail) int {
+		switch odin_api.TokenSortField(field) {
+		case odin_api.SortByMarketcap:
+			return a.Marketcap.Cmp(b.Marketcap)
+		case odin_api.SortByVolume:
+			return a.Volume.Cmp(b.Volume)
+		case odin_api.SortByCreatedTime:
+			return a.CreatedTime.Compare(b.CreatedTime)
+		case odin_api.SortByHolderCount:
+			return cmp.Compare(a.HolderCount, b.HolderCount)
+		case odin_api.SortByPrice:
+			return a.Price.Cmp(b.Price)
+		default:
+			return a.LastActionTime.Compare(b.LastActionTime)
+		}
+	}
+
+	slices.SortStableFunc(tokens, func(a, b odin_api.TokenDetail) int {
+		c := compare(a, b)
+		if c == 0 {
+			return strings.Compare(a.ID, b.ID)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+}
+
+// pagination 读取page和limit查询参数
+func pagination(r *http.Request, defaultLimit int) (page, limit int) {
+	page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	return page, limit
+}
+
+// paginate 返回第page页的数据和总数
+func paginate[T any](items []T, page, limit int) ([]T, int) {
+	start := (page - 1) * limit
+	if start >= len(items) {
+		return []T{}, len(items)
+	}
+	end := min(start+limit, len(items))
+	return items[start:end], len(items)
+}
+
+// issueToken 生成一个带exp声明的JWT格式令牌，签名部分为随机值
+func issueToken(principalID string, now, expiry time.Time) string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]any{
+		"sub": principalID,
+		"iat": now.Unix(),
+		"exp": expiry.Unix(),
+	})
+	return fmt.Sprintf("%s.%s.%s", header, enc.EncodeToString(claims), enc.EncodeToString([]byte(rand.Text())))
+}
+
+// writeJSON 以JSON格式写入响应
+func writeJSON(w http.ResponseWriter, v any) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 写入 {"message": ...} 格式的错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
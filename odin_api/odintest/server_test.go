@@ -0,0 +1,142 @@
+package odintest_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+func newServer(t *testing.T) *odintest.Server {
+	t.Helper()
+	srv := odintest.NewServer(odintest.Config{})
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAuthRegistersUser(t *testing.T) {
+	srv := newServer(t)
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal := identity.PrincipalText()
+
+	client := srv.NewClient()
+	token, err := client.AuthIdentity(identity)
+	if err != nil {
+		t.Fatalf("AuthIdentity: %v", err)
+	}
+	if _, ok := srv.User(principal); !ok {
+		t.Fatalf("user %s was not registered", principal)
+	}
+
+	client.SetToken(token)
+	user, err := client.ChangeUsername("trader", principal, token)
+	if err != nil {
+		t.Fatalf("ChangeUsername: %v", err)
+	}
+	if user.Username != "trader" {
+		t.Errorf("Username = %s, want trader", user.Username)
+	}
+	if got, _ := srv.User(principal); got.Username != "trader" {
+		t.Errorf("server username = %s, want trader", got.Username)
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	srv := newServer(t)
+	srv.AddToken(odin_api.TokenDetail{ID: "2jjj", Name: "ODIN", Ticker: "ODIN"})
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := srv.NewClient()
+	if _, err := client.PostComment("gm", identity.PrincipalText(), "2jjj"); !errors.Is(err, odin_api.ErrUnauthorized) {
+		t.Errorf("PostComment without token = %v, want ErrUnauthorized", err)
+	}
+
+	token, err := client.AuthIdentity(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetToken(token)
+	if _, err := client.PostComment("gm", identity.PrincipalText(), "2jjj"); err != nil {
+		t.Fatalf("PostComment: %v", err)
+	}
+
+	srv.ExpireTokens()
+	if _, err := client.PostComment("gn", identity.PrincipalText(), "2jjj"); !errors.Is(err, odin_api.ErrUnauthorized) {
+		t.Errorf("PostComment after ExpireTokens = %v, want ErrUnauthorized", err)
+	}
+	if comments := srv.Comments(); len(comments) != 1 || comments[0].Message != "gm" {
+		t.Errorf("comments = %+v, want only gm", comments)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{PathPrefix: "/currency", Status: http.StatusTooManyRequests, RetryAfter: 2 * time.Second, Times: 1})
+	client := srv.NewClient()
+
+	_, err := client.GetBTCPrice()
+	var apiErr *odin_api.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != 2*time.Second {
+		t.Fatalf("first GetBTCPrice = %v, want 429 with Retry-After 2s", err)
+	}
+	if _, err := client.GetBTCPrice(); err != nil {
+		t.Fatalf("GetBTCPrice after fault expired: %v", err)
+	}
+
+	srv.AddFault(odintest.Fault{Status: http.StatusInternalServerError})
+	if _, err := client.GetBTCPrice(); !errors.Is(err, odin_api.ErrServer) {
+		t.Errorf("GetBTCPrice with fault = %v, want ErrServer", err)
+	}
+	srv.ClearFaults()
+	if _, err := client.GetBTCPrice(); err != nil {
+		t.Errorf("GetBTCPrice after ClearFaults: %v", err)
+	}
+	if got := srv.Requests(); got != 4 {
+		t.Errorf("requests = %d, want 4", got)
+	}
+}
+
+func TestETag(t *testing.T) {
+	srv := newServer(t)
+	srv.SetBTCPrice(odin_api.BTCInfo{ID: 1, Symbol: "BTC", Amount: 65000})
+
+	resp, err := http.Get(srv.BaseURL() + "/currency/btc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.BaseURL()+"/currency/btc", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("status = %d, want 304", resp.StatusCode)
+	}
+
+	srv.SetBTCPrice(odin_api.BTCInfo{ID: 1, Symbol: "BTC", Amount: 66000})
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Errorf("status = %d, ETag = %s after change, want 200 with a new ETag", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+}
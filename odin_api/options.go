@@ -0,0 +1,48 @@
+package odin_api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option 用于配置Client的函数选项
+type Option func(*Client)
+
+// WithBaseURL 设置API的基础URL，例如测试环境或本地httptest服务器地址
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient 使用自定义的http.Client发送请求，可用于配置代理或自定义Transport
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithUserAgent 设置请求的User-Agent头
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader 添加一个随每个请求发送的默认请求头
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithTimeout 设置单个请求的超时时间
+// 与WithHTTPClient同时使用时，不会修改调用方传入的http.Client
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
@@ -0,0 +1,105 @@
+package odin_api
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultPageLimit 迭代器在未指定每页数量时使用的默认值
+const DefaultPageLimit = 100
+
+// pageInfo 响应中携带的分页信息
+type pageInfo struct {
+	count int // 总条数，0表示服务器未返回
+	limit int // 服务器实际使用的每页数量，0表示服务器未返回
+}
+
+// fetchPage 获取指定页的数据以及响应中的分页信息
+type fetchPage[T any] func(ctx context.Context, page, limit int) (data []T, info pageInfo, err error)
+
+// paginate 依次请求每一页并逐条产出数据
+// 响应带有总数时一直请求到已产出的条数达到总数为止；否则在数据少于响应回显的每页数量时停止
+// 服务器可能将limit截断为更小的值，因此不能与请求的limit比较；某页为空或出错时同样停止
+func paginate[T any](ctx context.Context, limit int, fetch fetchPage[T]) iter.Seq2[T, error] {
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+
+	return func(yield func(T, error) bool) {
+		seen := 0
+		for page := 1; ; page++ {
+			data, info, err := fetch(ctx, page, limit)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range data {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			seen += len(data)
+			if len(data) == 0 {
+				return
+			}
+			if info.count > 0 {
+				if seen >= info.count {
+					return
+				}
+				continue
+			}
+			pageSize := info.limit
+			if pageSize <= 0 {
+				pageSize = limit
+			}
+			if len(data) < pageSize {
+				return
+			}
+		}
+	}
+}
+
+// AllTokens 按最近交易时间遍历全部代币，limit为每页请求的数量
+func (c *Client) AllTokens(ctx context.Context, limit int) iter.Seq2[TokenDetail, error] {
+	return c.AllTokensQuery(ctx, TokenQuery{
+		Sort:      SortByLastActionTime,
+		Direction: SortDesc,
+		Limit:     limit,
+	})
+}
+
+// AllHolders 遍历代币的全部持有者
+func (c *Client) AllHolders(ctx context.Context, id string, limit int) iter.Seq2[Holder, error] {
+	return paginate(ctx, limit, func(ctx context.Context, page, limit int) ([]Holder, pageInfo, error) {
+		holders, err := c.GetHoldersPageCtx(ctx, id, page, limit)
+		if err != nil {
+			return nil, pageInfo{}, err
+		}
+		return holders.Data, pageInfo{count: int(holders.Count), limit: int(holders.Limit)}, nil
+	})
+}
+
+// AllTrades 遍历代币自target.LastActionTimestamp以来的全部交易
+func (c *Client) AllTrades(ctx context.Context, target TokenTarget, limit int) iter.Seq2[Trade, error] {
+	return paginate(ctx, limit, func(ctx context.Context, page, limit int) ([]Trade, pageInfo, error) {
+		trades, err := c.GetOdinFunTradesPageCtx(ctx, target, page, limit)
+		if err != nil {
+			return nil, pageInfo{}, err
+		}
+		return trades.Data, pageInfo{count: int(trades.Count), limit: int(trades.Limit)}, nil
+	})
+}
+
+// AllBalances 遍历用户的全部代币余额
+func (c *Client) AllBalances(ctx context.Context, principalID string, limit int) iter.Seq2[BalanceDetail, error] {
+	return paginate(ctx, limit, func(ctx context.Context, page, limit int) ([]BalanceDetail, pageInfo, error) {
+		balances, err := c.GetUserBalancesPageCtx(ctx, principalID, page, limit)
+		if err != nil {
+			return nil, pageInfo{}, err
+		}
+		return balances.Data, pageInfo{count: int(balances.Count), limit: int(balances.Limit)}, nil
+	})
+}
@@ -0,0 +1,131 @@
+package odin_api_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+// cappedHoldersServer 模拟将每页数量截断为maxLimit的服务器，withCount控制是否返回总数
+func cappedHoldersServer(t *testing.T, total, maxLimit int, withCount bool) (*odin_api.Client, *int) {
+	t.Helper()
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		limit = min(limit, maxLimit)
+
+		holders := odin_api.Holders{Data: []odin_api.Holder{}, Page: odin_api.Int(page), Limit: odin_api.Int(limit)}
+		for i := (page - 1) * limit; i < min(page*limit, total); i++ {
+			holders.Data = append(holders.Data, odin_api.Holder{User: fmt.Sprintf("user%d", i)})
+		}
+		if withCount {
+			holders.Count = odin_api.Int(total)
+		}
+		json.NewEncoder(w).Encode(holders)
+	}))
+	t.Cleanup(srv.Close)
+	return odin_api.NewClient(odin_api.WithBaseURL(srv.URL)), &requests
+}
+
+func collectHolders(t *testing.T, client *odin_api.Client, limit int) []odin_api.Holder {
+	t.Helper()
+	var got []odin_api.Holder
+	for holder, err := range client.AllHolders(context.Background(), "2jjj", limit) {
+		if err != nil {
+			t.Fatalf("AllHolders: %v", err)
+		}
+		got = append(got, holder)
+	}
+	return got
+}
+
+func TestAllHolders(t *testing.T) {
+	srv := newServer(t)
+	var holders []odin_api.Holder
+	for i := range 7 {
+		holders = append(holders, odin_api.Holder{User: fmt.Sprintf("user%d", i), Token: "2jjj"})
+	}
+	srv.SetHolders("2jjj", holders)
+
+	got := collectHolders(t, srv.NewClient(), 3)
+	if len(got) != len(holders) {
+		t.Fatalf("got %d holders, want %d", len(got), len(holders))
+	}
+	for i := range got {
+		if got[i].User != holders[i].User {
+			t.Errorf("holder %d = %s, want %s", i, got[i].User, holders[i].User)
+		}
+	}
+}
+
+func TestPaginateServerCapsLimitWithCount(t *testing.T) {
+	client, requests := cappedHoldersServer(t, 7, 3, true)
+	if got := collectHolders(t, client, 5); len(got) != 7 {
+		t.Errorf("got %d holders, want 7", len(got))
+	}
+	if *requests != 3 {
+		t.Errorf("requests = %d, want 3", *requests)
+	}
+}
+
+func TestPaginateServerCapsLimitWithoutCount(t *testing.T) {
+	// 没有总数时与响应回显的limit比较，而不是请求的limit
+	client, requests := cappedHoldersServer(t, 7, 3, false)
+	if got := collectHolders(t, client, 5); len(got) != 7 {
+		t.Errorf("got %d holders, want 7", len(got))
+	}
+	if *requests != 3 {
+		t.Errorf("requests = %d, want 3", *requests)
+	}
+}
+
+func TestPaginateStopsOnEmptyPage(t *testing.T) {
+	client, requests := cappedHoldersServer(t, 6, 3, false)
+	if got := collectHolders(t, client, 3); len(got) != 6 {
+		t.Errorf("got %d holders, want 6", len(got))
+	}
+	if *requests != 3 {
+		t.Errorf("requests = %d, want 3", *requests)
+	}
+}
+
+func TestPaginateBreak(t *testing.T) {
+	client, requests := cappedHoldersServer(t, 10, 2, true)
+	n := 0
+	for _, err := range client.AllHolders(context.Background(), "2jjj", 2) {
+		if err != nil {
+			t.Fatalf("AllHolders: %v", err)
+		}
+		if n++; n == 3 {
+			break
+		}
+	}
+	if *requests != 2 {
+		t.Errorf("requests = %d, want 2", *requests)
+	}
+}
+
+func TestPaginateError(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{PathPrefix: "/token/2jjj/owners", Status: http.StatusInternalServerError})
+
+	var errs int
+	for _, err := range srv.NewClient().AllHolders(context.Background(), "2jjj", 10) {
+		if err == nil {
+			t.Fatal("AllHolders yielded an item, want error")
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Errorf("yielded %d errors, want 1", errs)
+	}
+}
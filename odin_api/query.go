@@ -0,0 +1,123 @@
+package odin_api
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// TokenSortField /tokens端点支持的排序字段
+type TokenSortField string
+
+const (
+	SortByLastActionTime TokenSortField = "last_action_time"
+	SortByMarketcap      TokenSortField = "marketcap"
+	SortByVolume         TokenSortField = "volume"
+	SortByCreatedTime    TokenSortField = "created_time"
+	SortByHolderCount    TokenSortField = "holder_count"
+	SortByPrice          TokenSortField = "price"
+)
+
+// SortDirection 排序方向
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// TokenQuery /tokens端点的查询条件
+// 零值字段不会出现在查询字符串中
+type TokenQuery struct {
+	Sort      TokenSortField // 排序字段
+	Direction SortDirection  // 排序方向，未指定时为降序
+	Bonded    *bool          // 是否已绑定曲线毕业
+	Featured  *bool          // 是否为精选代币
+	Trading   *bool          // 是否开放交易
+	Search    string         // 按名称或代码搜索
+	Page      int            // 页码，从1开始
+	Limit     int            // 每页数量
+}
+
+// Bool 返回指向v的指针，便于设置TokenQuery中的过滤条件
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Values 将查询条件编码为url.Values
+func (q TokenQuery) Values() url.Values {
+	values := url.Values{}
+	if q.Sort != "" {
+		direction := q.Direction
+		if direction == "" {
+			direction = SortDesc
+		}
+		values.Set("sort", fmt.Sprintf("%s:%s", q.Sort, direction))
+	}
+	if q.Bonded != nil {
+		values.Set("bonded", strconv.FormatBool(*q.Bonded))
+	}
+	if q.Featured != nil {
+		values.Set("featured", strconv.FormatBool(*q.Featured))
+	}
+	if q.Trading != nil {
+		values.Set("trading", strconv.FormatBool(*q.Trading))
+	}
+	if q.Search != "" {
+		values.Set("search", q.Search)
+	}
+	if q.Page > 0 {
+		values.Set("page", strconv.Itoa(q.Page))
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return values
+}
+
+// Endpoint 返回带有查询字符串的/tokens端点
+func (q TokenQuery) Endpoint() string {
+	if encoded := q.Values().Encode(); encoded != "" {
+		return "/tokens?" + encoded
+	}
+	return "/tokens"
+}
+
+// GetTokens 按查询条件获取代币列表
+func (c *Client) GetTokens(query TokenQuery) (*OdinFunTokens, error) {
+	return c.GetTokensCtx(context.Background(), query)
+}
+
+// GetTokensCtx 与GetTokens相同，但使用ctx控制请求的取消和超时
+func (c *Client) GetTokensCtx(ctx context.Context, query TokenQuery) (*OdinFunTokens, error) {
+	// 发送请求
+	endpoint := query.Endpoint()
+	resp, err := c.GetCtx(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("获取代币列表失败: %w", err)
+	}
+
+	// 解析响应
+	var tokens OdinFunTokens
+	if err := c.decode(endpoint, resp, &tokens); err != nil {
+		return nil, fmt.Errorf("解析代币列表失败: %w", err)
+	}
+
+	return &tokens, nil
+}
+
+// AllTokensQuery 按查询条件遍历全部代币
+// query.Page被忽略，迭代总是从第一页开始；query.Limit为每页请求的数量
+func (c *Client) AllTokensQuery(ctx context.Context, query TokenQuery) iter.Seq2[TokenDetail, error] {
+	return paginate(ctx, query.Limit, func(ctx context.Context, page, limit int) ([]TokenDetail, pageInfo, error) {
+		q := query
+		q.Page, q.Limit = page, limit
+		tokens, err := c.GetTokensCtx(ctx, q)
+		if err != nil {
+			return nil, pageInfo{}, err
+		}
+		return tokens.Data, pageInfo{count: int(tokens.Count), limit: int(tokens.Limit)}, nil
+	})
+}
@@ -0,0 +1,76 @@
+package odin_api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+func TestTokenQueryEndpoint(t *testing.T) {
+	tests := []struct {
+		name  string
+		query odin_api.TokenQuery
+		want  string
+	}{
+		{"zero value", odin_api.TokenQuery{}, "/tokens"},
+		{"default direction", odin_api.TokenQuery{Sort: odin_api.SortByMarketcap}, "/tokens?sort=marketcap%3Adesc"},
+		{"ascending", odin_api.TokenQuery{Sort: odin_api.SortByPrice, Direction: odin_api.SortAsc}, "/tokens?sort=price%3Aasc"},
+		{"direction without sort", odin_api.TokenQuery{Direction: odin_api.SortAsc}, "/tokens"},
+		{"false filter", odin_api.TokenQuery{Bonded: odin_api.Bool(false)}, "/tokens?bonded=false"},
+		{
+			"all fields",
+			odin_api.TokenQuery{
+				Sort:     odin_api.SortByVolume,
+				Bonded:   odin_api.Bool(true),
+				Featured: odin_api.Bool(true),
+				Trading:  odin_api.Bool(false),
+				Search:   "odin dog&cat",
+				Page:     2,
+				Limit:    50,
+			},
+			"/tokens?bonded=true&featured=true&limit=50&page=2&search=odin+dog%26cat&sort=volume%3Adesc&trading=false",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Endpoint(); got != tt.want {
+				t.Errorf("Endpoint() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTokensQuery(t *testing.T) {
+	srv := newServer(t)
+	srv.AddToken(odin_api.TokenDetail{ID: "2aaa", Name: "Alpha", Ticker: "ALPHA", Bonded: true})
+	srv.AddToken(odin_api.TokenDetail{ID: "2bbb", Name: "Beta", Ticker: "BETA"})
+
+	tokens, err := srv.NewClient().GetTokens(odin_api.TokenQuery{
+		Sort:   odin_api.SortByMarketcap,
+		Bonded: odin_api.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("GetTokens: %v", err)
+	}
+	if len(tokens.Data) != 1 || tokens.Data[0].ID != "2aaa" {
+		t.Errorf("tokens = %+v, want only the bonded token", tokens.Data)
+	}
+}
+
+func TestAllTokensQueryIgnoresPage(t *testing.T) {
+	srv := newServer(t)
+	srv.AddToken(odin_api.TokenDetail{ID: "2aaa", Name: "Alpha", Ticker: "ALPHA"})
+	srv.AddToken(odin_api.TokenDetail{ID: "2bbb", Name: "Beta", Ticker: "BETA"})
+
+	var ids []string
+	for token, err := range srv.NewClient().AllTokensQuery(context.Background(), odin_api.TokenQuery{Page: 3, Limit: 1}) {
+		if err != nil {
+			t.Fatalf("AllTokensQuery: %v", err)
+		}
+		ids = append(ids, token.ID)
+	}
+	if len(ids) != 3 {
+		t.Errorf("ids = %v, want all 3 tokens", ids)
+	}
+}
@@ -0,0 +1,172 @@
+package odin_api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MrHat365/odin-go/metrics"
+)
+
+// EndpointGroup 端点分组，用于为不同类别的端点配置独立的限流器
+type EndpointGroup string
+
+const (
+	// EndpointGroupMarket 市场数据端点，例如 /tokens、/token/{id}、/currency/btc
+	EndpointGroupMarket EndpointGroup = "market"
+	// EndpointGroupUser 用户和认证相关端点，例如 /auth、/user/{id}
+	EndpointGroupUser EndpointGroup = "user"
+)
+
+// EndpointGroupOf 返回端点所属的分组
+func EndpointGroupOf(endpoint string) EndpointGroup {
+	path := endpoint
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	switch {
+	case path == "/auth", path == "/user", strings.HasPrefix(path, "/user/"):
+		return EndpointGroupUser
+	default:
+		return EndpointGroupMarket
+	}
+}
+
+// RateLimiter 令牌桶限流器，可在多个goroutine和多个Client之间共享
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数
+	burst  float64 // 桶容量
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter 创建每秒允许rate个请求、最大突发为burst的限流器
+// burst小于1时按1处理
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow 在不等待的情况下尝试获取一个令牌
+func (l *RateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait 阻塞直到获得一个令牌，ctx被取消或超时时返回ctx.Err()
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+	// 预先扣除令牌，令牌数可能变为负数，表示排队中的请求
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		if l.rate <= 0 {
+			l.tokens++
+			l.mu.Unlock()
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		wait = time.Duration(math.Ceil(-l.tokens / l.rate * float64(time.Second)))
+	}
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, wait); err != nil {
+		// 归还未使用的令牌
+		l.mu.Lock()
+		l.tokens = math.Min(l.tokens+1, l.burst)
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// refill 根据经过的时间补充令牌，调用方需持有锁
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	l.last = now
+	l.tokens = math.Min(l.tokens+elapsed*l.rate, l.burst)
+}
+
+// WithRateLimiter 为客户端的所有请求设置限流器
+// 同一个RateLimiter可传给多个Client以共享配额
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithEndpointRateLimiter 为指定端点分组设置额外的限流器
+// 请求会先后等待全局限流器和分组限流器
+func WithEndpointRateLimiter(group EndpointGroup, limiter *RateLimiter) Option {
+	return func(c *Client) {
+		if c.groupLimiters == nil {
+			c.groupLimiters = make(map[EndpointGroup]*RateLimiter)
+		}
+		c.groupLimiters[group] = limiter
+	}
+}
+
+// RateLimitMiddleware 在发送每个请求（包括重试）之前等待limiter
+func RateLimitMiddleware(limiter *RateLimiter) Middleware {
+	return limitMiddleware("", limiter, nil)
+}
+
+// EndpointRateLimitMiddleware 在发送属于group的请求之前等待limiter，其他请求不受影响
+func EndpointRateLimitMiddleware(group EndpointGroup, limiter *RateLimiter) Middleware {
+	return limitMiddleware(group, limiter, nil)
+}
+
+// limitMiddleware 等待limiter，group为空时作用于所有请求
+// recorder不为nil时记录等待时间
+func limitMiddleware(group EndpointGroup, limiter *RateLimiter, recorder metrics.Recorder) Middleware {
+	label := string(group)
+	if label == "" {
+		label = "all"
+	}
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if group == "" || EndpointGroupOf(RequestEndpoint(req)) == group {
+				start := time.Now()
+				if err := limiter.Wait(req.Context()); err != nil {
+					return nil, fmt.Errorf("等待限流失败: %w", err)
+				}
+				if recorder != nil {
+					recorder.Observe(metrics.RateLimitWait, time.Since(start).Seconds(), metrics.L("group", label))
+				}
+			}
+			return next.Do(req)
+		})
+	}
+}
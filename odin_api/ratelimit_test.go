@@ -0,0 +1,88 @@
+package odin_api_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+func TestRateLimiterAllowBurst(t *testing.T) {
+	limiter := odin_api.NewRateLimiter(1, 3)
+	for i := range 3 {
+		if !limiter.Allow() {
+			t.Fatalf("Allow() #%d = false, want true within burst", i+1)
+		}
+	}
+	if limiter.Allow() {
+		t.Error("Allow() after burst = true, want false")
+	}
+}
+
+func TestRateLimiterWaitPaces(t *testing.T) {
+	limiter := odin_api.NewRateLimiter(100, 1)
+	start := time.Now()
+	for range 6 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	// 第一个令牌来自突发，其余5个每10毫秒补充一个
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 45ms", elapsed)
+	}
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	limiter := odin_api.NewRateLimiter(0.001, 1)
+	limiter.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait = %v, want DeadlineExceeded", err)
+	}
+	// 取消的等待会归还令牌，不会让后续请求多等一个周期
+	if limiter.Allow() {
+		t.Error("Allow() = true, want false while bucket is empty")
+	}
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	srv := newServer(t)
+	client := srv.NewClient(odin_api.WithRateLimiter(odin_api.NewRateLimiter(50, 1)))
+
+	start := time.Now()
+	for range 4 {
+		if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+			t.Fatalf("GetOdinFunToken: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 55*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 55ms for 3 paced requests", elapsed)
+	}
+}
+
+func TestEndpointRateLimiterOnlyAffectsGroup(t *testing.T) {
+	srv := newServer(t)
+	srv.AddUser(odin_api.OdinUser{Principal: "user-1", Username: "alice"})
+	limiter := odin_api.NewRateLimiter(0.001, 1)
+	client := srv.NewClient(odin_api.WithEndpointRateLimiter(odin_api.EndpointGroupUser, limiter))
+
+	if _, err := client.GetOdinFunUser("user-1"); err != nil {
+		t.Fatalf("GetOdinFunUser: %v", err)
+	}
+	for range 3 {
+		if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+			t.Fatalf("GetOdinFunToken: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetOdinFunUserCtx(ctx, "user-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second GetOdinFunUserCtx = %v, want DeadlineExceeded", err)
+	}
+}
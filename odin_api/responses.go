@@ -1,147 +1,170 @@
 package odin_api
 
-import "time"
+import (
+	"time"
+
+	"github.com/MrHat365/odin-go/amounts"
+)
 
 type OdinUser struct {
-	Principal          string      `json:"principal"`
-	Username           string      `json:"username"`
-	Bio                interface{} `json:"bio"`
-	Image              interface{} `json:"image"`
-	Referrer           interface{} `json:"referrer"`
-	Admin              bool        `json:"admin"`
-	RefCode            string      `json:"ref_code"`
-	Profit             interface{} `json:"profit"`
-	TotalAssetValue    interface{} `json:"total_asset_value"`
-	ReferralEarnings   int         `json:"referral_earnings"`
-	ReferralCount      int         `json:"referral_count"`
-	AccessAllowed      bool        `json:"access_allowed"`
-	BetaAccessCodes    string      `json:"beta_access_codes"`
-	BtcDepositAddress  string      `json:"btc_deposit_address"`
-	BtcWalletAddress   string      `json:"btc_wallet_address"`
-	BlifeID            string      `json:"blife_id"`
-	CreatedAt          time.Time   `json:"created_at"`
-	RuneDepositAddress string      `json:"rune_deposit_address"`
+	Principal          string                      `json:"principal"`
+	Username           string                      `json:"username"`
+	Bio                Optional[string]            `json:"bio"`
+	Image              Optional[string]            `json:"image"`
+	Referrer           Optional[string]            `json:"referrer"`
+	Admin              bool                        `json:"admin"`
+	RefCode            string                      `json:"ref_code"`
+	Profit             Optional[amounts.MilliSats] `json:"profit"`
+	TotalAssetValue    Optional[amounts.MilliSats] `json:"total_asset_value"`
+	ReferralEarnings   amounts.MilliSats           `json:"referral_earnings"`
+	ReferralCount      Int                         `json:"referral_count"`
+	AccessAllowed      bool                        `json:"access_allowed"`
+	BetaAccessCodes    string                      `json:"beta_access_codes"`
+	BtcDepositAddress  string                      `json:"btc_deposit_address"`
+	BtcWalletAddress   string                      `json:"btc_wallet_address"`
+	BlifeID            string                      `json:"blife_id"`
+	CreatedAt          time.Time                   `json:"created_at"`
+	RuneDepositAddress string                      `json:"rune_deposit_address"`
 }
 
 type OdinUserBalance struct {
 	Data  []BalanceDetail `json:"data"`
-	Page  int             `json:"page"`
-	Limit int             `json:"limit"`
-	Count int             `json:"count"`
+	Page  Int             `json:"page"`
+	Limit Int             `json:"limit"`
+	Count Int             `json:"count"`
 }
 
 type BalanceDetail struct {
-	ID           string      `json:"id"`
-	Ticker       string      `json:"ticker"`
-	Rune         string      `json:"rune"`
-	Name         string      `json:"name"`
-	Balance      int         `json:"balance"`
-	Image        interface{} `json:"image"`
-	Divisibility int         `json:"divisibility"`
-	Decimals     int         `json:"decimals"`
-	RuneID       string      `json:"rune_id"`
-	Trading      bool        `json:"trading"`
-	Deposits     bool        `json:"deposits"`
-	Withdrawals  bool        `json:"withdrawals"`
+	ID           string             `json:"id"`
+	Ticker       string             `json:"ticker"`
+	Rune         string             `json:"rune"`
+	Name         string             `json:"name"`
+	Balance      amounts.TokenUnits `json:"balance"`
+	Image        Optional[string]   `json:"image"`
+	Divisibility Int                `json:"divisibility"`
+	Decimals     Int                `json:"decimals"`
+	RuneID       string             `json:"rune_id"`
+	Trading      bool               `json:"trading"`
+	Deposits     bool               `json:"deposits"`
+	Withdrawals  bool               `json:"withdrawals"`
 }
 
 type OdinFunTokens struct {
 	Data  []TokenDetail `json:"data"`
-	Page  int           `json:"page"`
-	Limit int           `json:"limit"`
-	Count int           `json:"count"`
+	Page  Int           `json:"page"`
+	Limit Int           `json:"limit"`
+	Count Int           `json:"count"`
 }
 
 type TokenDetail struct {
-	ID                 string      `json:"id"`
-	Name               string      `json:"name"`
-	Description        string      `json:"description"`
-	Image              string      `json:"image"`
-	Creator            string      `json:"creator"`
-	CreatedTime        time.Time   `json:"created_time"`
-	Volume             int         `json:"volume"`
-	Bonded             bool        `json:"bonded"`
-	IcrcLedger         string      `json:"icrc_ledger"`
-	Price              int         `json:"price"`
-	Marketcap          int64       `json:"marketcap"`
-	Rune               string      `json:"rune"`
-	Featured           bool        `json:"featured"`
-	HolderCount        int         `json:"holder_count"`
-	HolderTop          int         `json:"holder_top"`
-	HolderDev          int         `json:"holder_dev"`
-	CommentCount       int         `json:"comment_count"`
-	Sold               int64       `json:"sold"`
-	Twitter            string      `json:"twitter"`
-	Website            string      `json:"website"`
-	Telegram           string      `json:"telegram"`
-	LastCommentTime    interface{} `json:"last_comment_time"`
-	SellCount          int         `json:"sell_count"`
-	BuyCount           int         `json:"buy_count"`
-	Ticker             string      `json:"ticker"`
-	BtcLiquidity       int         `json:"btc_liquidity"`
-	TokenLiquidity     int         `json:"token_liquidity"`
-	UserBtcLiquidity   int         `json:"user_btc_liquidity"`
-	UserTokenLiquidity int         `json:"user_token_liquidity"`
-	UserLpTokens       int         `json:"user_lp_tokens"`
-	TotalSupply        int64       `json:"total_supply"`
-	SwapFees           int         `json:"swap_fees"`
-	SwapFees24         int         `json:"swap_fees_24"`
-	SwapVolume         int         `json:"swap_volume"`
-	SwapVolume24       int         `json:"swap_volume_24"`
-	Threshold          int64       `json:"threshold"`
-	TxnCount           int         `json:"txn_count"`
-	Divisibility       int         `json:"divisibility"`
-	Decimals           int         `json:"decimals"`
-	Withdrawals        bool        `json:"withdrawals"`
-	Deposits           bool        `json:"deposits"`
-	Trading            bool        `json:"trading"`
-	External           bool        `json:"external"`
-	Price5M            int         `json:"price_5m"`
-	Price1H            int         `json:"price_1h"`
-	Price6H            int         `json:"price_6h"`
-	Price1D            int         `json:"price_1d"`
-	RuneID             string      `json:"rune_id"`
-	LastActionTime     time.Time   `json:"last_action_time"`
-	TwitterVerified    bool        `json:"twitter_verified"`
+	ID                 string              `json:"id"`
+	Name               string              `json:"name"`
+	Description        string              `json:"description"`
+	Image              string              `json:"image"`
+	Creator            string              `json:"creator"`
+	CreatedTime        time.Time           `json:"created_time"`
+	Volume             amounts.MilliSats   `json:"volume"`
+	Bonded             bool                `json:"bonded"`
+	IcrcLedger         string              `json:"icrc_ledger"`
+	Price              amounts.MilliSats   `json:"price"`
+	Marketcap          amounts.MilliSats   `json:"marketcap"`
+	Rune               string              `json:"rune"`
+	Featured           bool                `json:"featured"`
+	HolderCount        Int                 `json:"holder_count"`
+	HolderTop          Int                 `json:"holder_top"`
+	HolderDev          Int                 `json:"holder_dev"`
+	CommentCount       Int                 `json:"comment_count"`
+	Sold               amounts.TokenUnits  `json:"sold"`
+	Twitter            string              `json:"twitter"`
+	Website            string              `json:"website"`
+	Telegram           string              `json:"telegram"`
+	LastCommentTime    Optional[Timestamp] `json:"last_comment_time"`
+	SellCount          Int                 `json:"sell_count"`
+	BuyCount           Int                 `json:"buy_count"`
+	Ticker             string              `json:"ticker"`
+	BtcLiquidity       amounts.MilliSats   `json:"btc_liquidity"`
+	TokenLiquidity     amounts.TokenUnits  `json:"token_liquidity"`
+	UserBtcLiquidity   amounts.MilliSats   `json:"user_btc_liquidity"`
+	UserTokenLiquidity amounts.TokenUnits  `json:"user_token_liquidity"`
+	UserLpTokens       Int                 `json:"user_lp_tokens"`
+	TotalSupply        amounts.TokenUnits  `json:"total_supply"`
+	SwapFees           amounts.MilliSats   `json:"swap_fees"`
+	SwapFees24         amounts.MilliSats   `json:"swap_fees_24"`
+	SwapVolume         amounts.MilliSats   `json:"swap_volume"`
+	SwapVolume24       amounts.MilliSats   `json:"swap_volume_24"`
+	Threshold          amounts.MilliSats   `json:"threshold"`
+	TxnCount           Int                 `json:"txn_count"`
+	Divisibility       Int                 `json:"divisibility"`
+	Decimals           Int                 `json:"decimals"`
+	Withdrawals        bool                `json:"withdrawals"`
+	Deposits           bool                `json:"deposits"`
+	Trading            bool                `json:"trading"`
+	External           bool                `json:"external"`
+	Price5M            amounts.MilliSats   `json:"price_5m"`
+	Price1H            amounts.MilliSats   `json:"price_1h"`
+	Price6H            amounts.MilliSats   `json:"price_6h"`
+	Price1D            amounts.MilliSats   `json:"price_1d"`
+	RuneID             string              `json:"rune_id"`
+	LastActionTime     time.Time           `json:"last_action_time"`
+	TwitterVerified    bool                `json:"twitter_verified"`
 }
 
 type Holders struct {
-	Data []struct {
-		User         string `json:"user"`
-		Token        string `json:"token"`
-		Balance      int64  `json:"balance"`
-		UserUsername string `json:"user_username"`
-		UserImage    string `json:"user_image"`
-	} `json:"data"`
-	Page  int `json:"page"`
-	Limit int `json:"limit"`
-	Count int `json:"count"`
+	Data  []Holder `json:"data"`
+	Page  Int      `json:"page"`
+	Limit Int      `json:"limit"`
+	Count Int      `json:"count"`
+}
+
+type Holder struct {
+	User         string             `json:"user"`
+	Token        string             `json:"token"`
+	Balance      amounts.TokenUnits `json:"balance"`
+	UserUsername string             `json:"user_username"`
+	UserImage    string             `json:"user_image"`
 }
 
 type TokenTraders struct {
-	Data []struct {
-		ID           string      `json:"id"`
-		User         string      `json:"user"`
-		Token        string      `json:"token"`
-		Time         time.Time   `json:"time"`
-		Buy          bool        `json:"buy"`
-		AmountBtc    int         `json:"amount_btc"`
-		AmountToken  int64       `json:"amount_token"`
-		Price        int         `json:"price"`
-		Bonded       bool        `json:"bonded"`
-		UserUsername string      `json:"user_username"`
-		UserImage    interface{} `json:"user_image"`
-		Decimals     int         `json:"decimals"`
-		Divisibility int         `json:"divisibility"`
-	} `json:"data"`
-	Page  int `json:"page"`
-	Limit int `json:"limit"`
-	Count int `json:"count"`
+	Data  []Trade `json:"data"`
+	Page  Int     `json:"page"`
+	Limit Int     `json:"limit"`
+	Count Int     `json:"count"`
+}
+
+type Trade struct {
+	ID           string             `json:"id"`
+	User         string             `json:"user"`
+	Token        string             `json:"token"`
+	Time         time.Time          `json:"time"`
+	Buy          bool               `json:"buy"`
+	AmountBtc    amounts.MilliSats  `json:"amount_btc"`
+	AmountToken  amounts.TokenUnits `json:"amount_token"`
+	Price        amounts.MilliSats  `json:"price"`
+	Bonded       bool               `json:"bonded"`
+	UserUsername string             `json:"user_username"`
+	UserImage    Optional[string]   `json:"user_image"`
+	Decimals     Int                `json:"decimals"`
+	Divisibility Int                `json:"divisibility"`
 }
 
 type BTCInfo struct {
-	ID       int       `json:"id"`
+	ID       Int       `json:"id"`
 	Symbol   string    `json:"symbol"`
 	Datetime time.Time `json:"datetime"`
-	Amount   float64   `json:"amount"`
+	Amount   Float     `json:"amount"`
+}
+
+// Scale 返回余额的小数位数，用于格式化Balance
+func (b BalanceDetail) Scale() int {
+	return amounts.Scale(int(b.Divisibility), int(b.Decimals))
+}
+
+// Scale 返回代币的小数位数，用于格式化代币数量
+func (t TokenDetail) Scale() int {
+	return amounts.Scale(int(t.Divisibility), int(t.Decimals))
+}
+
+// Scale 返回交易代币的小数位数，用于格式化AmountToken
+func (t Trade) Scale() int {
+	return amounts.Scale(int(t.Divisibility), int(t.Decimals))
 }
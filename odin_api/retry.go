@@ -0,0 +1,183 @@
+package odin_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy 配置请求失败时的自动重试行为
+// 零值表示不重试
+type RetryPolicy struct {
+	MaxAttempts int           // 包括首次请求在内的最大尝试次数，小于等于1表示不重试
+	BaseDelay   time.Duration // 指数退避的初始等待时间
+	MaxDelay    time.Duration // 单次退避等待的上限；服务器要求的Retry-After超过该值时不再重试
+	RetryPost   bool          // 是否对非幂等的POST请求进行重试，默认仅重试GET
+
+	// ShouldRetry 自定义是否重试的判断，为nil时使用IsRetryable
+	ShouldRetry func(err error) bool
+	// OnAttempt 每次尝试结束后调用，可用于记录日志或统计
+	OnAttempt func(RetryAttempt)
+}
+
+// RetryAttempt 描述一次请求尝试的结果
+type RetryAttempt struct {
+	Method   string
+	Endpoint string
+	Attempt  int           // 从1开始的尝试序号
+	Err      error         // 本次尝试的错误，成功时为nil
+	Delay    time.Duration // 下一次重试前的等待时间，不再重试时为0
+	Retrying bool          // 是否会进行下一次重试
+}
+
+// DefaultRetryPolicy 返回默认的重试策略：最多3次尝试，500毫秒起步的指数退避，上限10秒
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// WithRetryPolicy 为客户端设置重试策略
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// IsRetryable 判断错误是否属于可重试的临时错误
+// 429、500、502、503、504以及网络错误视为可重试，context的取消和超时不重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	// 其他错误来自传输层，例如连接被重置
+	return true
+}
+
+// allows 判断该策略是否允许对指定方法的请求进行重试
+func (p RetryPolicy) allows(method string) bool {
+	if p.MaxAttempts <= 1 {
+		return false
+	}
+	return method == http.MethodGet || method == http.MethodHead || p.RetryPost
+}
+
+// shouldRetry 判断错误是否应当重试
+func (p RetryPolicy) shouldRetry(err error) bool {
+	if p.ShouldRetry != nil {
+		return p.ShouldRetry(err)
+	}
+	return IsRetryable(err)
+}
+
+// delay 计算第attempt次尝试失败后的等待时间
+// 服务器给出Retry-After时必须等待该时长，超过MaxDelay时ok为false，表示不再重试；
+// 否则使用带完全抖动的指数退避
+func (p RetryPolicy) delay(attempt int, err error) (d time.Duration, ok bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		if p.MaxDelay > 0 && apiErr.RetryAfter > p.MaxDelay {
+			return 0, false
+		}
+		return apiErr.RetryAfter, true
+	}
+
+	if p.BaseDelay <= 0 {
+		return 0, true
+	}
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff <= 0 {
+		// 位移溢出
+		backoff = time.Duration(1<<63 - 1)
+	}
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	return rand.N(backoff) + 1, true
+}
+
+// sleepContext 等待d时长，ctx被取消时提前返回ctx.Err()
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RetryMiddleware 按照policy重试临时错误，每次重试都会重新构建请求体
+// 内层中间件可以通过RequestAttempt获得当前的尝试序号
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			endpoint := RequestEndpoint(req)
+			retryable := policy.allows(req.Method)
+
+			for attempt := 1; ; attempt++ {
+				attemptReq := req
+				if attempt > 1 {
+					var err error
+					if attemptReq, err = rewind(req); err != nil {
+						return nil, err
+					}
+				}
+				attemptReq = attemptReq.WithContext(context.WithValue(ctx, attemptKey, attempt))
+
+				resp, err := next.Do(attemptReq)
+
+				retrying := err != nil && retryable && attempt < policy.MaxAttempts && policy.shouldRetry(err)
+				var delay time.Duration
+				if retrying {
+					delay, retrying = policy.delay(attempt, err)
+				}
+				if policy.OnAttempt != nil {
+					policy.OnAttempt(RetryAttempt{
+						Method:   req.Method,
+						Endpoint: endpoint,
+						Attempt:  attempt,
+						Err:      err,
+						Delay:    delay,
+						Retrying: retrying,
+					})
+				}
+				if !retrying {
+					return resp, err
+				}
+
+				if err := sleepContext(ctx, delay); err != nil {
+					return nil, fmt.Errorf("等待重试时中止: %w", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package odin_api_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+)
+
+// newServer 启动预置了一个代币的模拟服务器
+func newServer(t *testing.T) *odintest.Server {
+	t.Helper()
+
+	srv := odintest.NewServer(odintest.Config{})
+	t.Cleanup(srv.Close)
+	srv.AddToken(odin_api.TokenDetail{ID: "2jjj", Name: "ODIN", Ticker: "ODIN"})
+	return srv
+}
+
+func TestRetryRecoversFromServerErrors(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{PathPrefix: "/token/", Status: http.StatusServiceUnavailable, Times: 2})
+
+	var (
+		mu       sync.Mutex
+		attempts []odin_api.RetryAttempt
+	)
+	client := srv.NewClient(odin_api.WithRetryPolicy(odin_api.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+		OnAttempt: func(a odin_api.RetryAttempt) {
+			mu.Lock()
+			attempts = append(attempts, a)
+			mu.Unlock()
+		},
+	}))
+
+	token, err := client.GetOdinFunToken("2jjj")
+	if err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+	if token.Name != "ODIN" {
+		t.Errorf("Name = %q, want ODIN", token.Name)
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(attempts))
+	}
+	for i, a := range attempts[:2] {
+		if !a.Retrying || !errors.Is(a.Err, odin_api.ErrServer) {
+			t.Errorf("attempt %d = %+v, want retrying server error", i+1, a)
+		}
+		if a.Delay <= 0 || a.Delay > 5*time.Millisecond {
+			t.Errorf("attempt %d delay = %v, want (0, 5ms]", i+1, a.Delay)
+		}
+	}
+	if last := attempts[2]; last.Err != nil || last.Retrying || last.Endpoint != "/token/2jjj" {
+		t.Errorf("last attempt = %+v, want success on /token/2jjj", last)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{Status: http.StatusBadGateway})
+	client := srv.NewClient(odin_api.WithRetryPolicy(odin_api.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	_, err := client.GetOdinFunToken("2jjj")
+	if !errors.Is(err, odin_api.ErrServer) {
+		t.Fatalf("err = %v, want ErrServer", err)
+	}
+	if got := srv.Requests(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestRetryAfterRespected(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{Status: http.StatusTooManyRequests, RetryAfter: time.Second, Times: 1})
+
+	var delays []time.Duration
+	client := srv.NewClient(odin_api.WithRetryPolicy(odin_api.RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		OnAttempt: func(a odin_api.RetryAttempt) {
+			delays = append(delays, a.Delay)
+		},
+	}))
+
+	start := time.Now()
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+	if len(delays) != 2 || delays[0] != time.Second {
+		t.Errorf("delays = %v, want first delay equal to Retry-After", delays)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, before Retry-After", elapsed)
+	}
+}
+
+func TestRetryAfterBeyondMaxDelayStops(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{Status: http.StatusTooManyRequests, RetryAfter: 30 * time.Second, Times: 1})
+
+	var attempts []odin_api.RetryAttempt
+	client := srv.NewClient(odin_api.WithRetryPolicy(odin_api.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+		OnAttempt: func(a odin_api.RetryAttempt) {
+			attempts = append(attempts, a)
+		},
+	}))
+
+	// 不能在服务器允许之前重试，直接返回429
+	_, err := client.GetOdinFunToken("2jjj")
+	var apiErr *odin_api.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != 30*time.Second {
+		t.Fatalf("err = %v, want 429 with Retry-After 30s", err)
+	}
+	if len(attempts) != 1 || attempts[0].Retrying || attempts[0].Delay != 0 {
+		t.Errorf("attempts = %+v, want a single attempt without retry", attempts)
+	}
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestRetrySkipsPost(t *testing.T) {
+	srv := newServer(t)
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.AddFault(odintest.Fault{PathPrefix: "/auth", Status: http.StatusServiceUnavailable, Times: 1})
+	client := srv.NewClient(odin_api.WithRetryPolicy(odin_api.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	if _, err := client.AuthIdentity(identity); !errors.Is(err, odin_api.ErrServer) {
+		t.Fatalf("err = %v, want ErrServer", err)
+	}
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&odin_api.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{&odin_api.APIError{StatusCode: http.StatusInternalServerError}, true},
+		{&odin_api.APIError{StatusCode: http.StatusGatewayTimeout}, true},
+		{&odin_api.APIError{StatusCode: http.StatusNotImplemented}, false},
+		{&odin_api.APIError{StatusCode: http.StatusNotFound}, false},
+		{fmt.Errorf("发送请求失败: %w", context.DeadlineExceeded), false},
+		{context.Canceled, false},
+		{errors.New("connection reset by peer"), true},
+	}
+	for _, tt := range tests {
+		if got := odin_api.IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
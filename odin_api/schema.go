@@ -0,0 +1,246 @@
+package odin_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/MrHat365/odin-go/metrics"
+)
+
+// SchemaDrift 响应与Go结构体之间的差异
+type SchemaDrift struct {
+	Endpoint string   // 请求的端点，包含路径参数和查询参数
+	Route    string   // 去掉ID和查询参数后的端点，例如 /token/{id}/trades
+	Type     string   // 解析目标的Go类型名称
+	Unknown  []string // 响应中存在但结构体中没有的字段路径，例如 data[].new_field
+	Missing  []string // 结构体中存在但响应中缺失的字段路径
+}
+
+// Empty 判断是否没有差异
+func (d SchemaDrift) Empty() bool {
+	return len(d.Unknown) == 0 && len(d.Missing) == 0
+}
+
+// String 返回差异的文本描述
+func (d SchemaDrift) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)", d.Route, d.Type)
+	if len(d.Unknown) > 0 {
+		fmt.Fprintf(&b, " 未知字段: %s", strings.Join(d.Unknown, ", "))
+	}
+	if len(d.Missing) > 0 {
+		fmt.Fprintf(&b, " 缺失字段: %s", strings.Join(d.Missing, ", "))
+	}
+	return b.String()
+}
+
+// WithStrictDecoding 开启严格解析模式
+// 每次解析响应时比较JSON字段与目标结构体，存在差异时调用onDrift；
+// 同时设置了WithMetrics时还会累加metrics.SchemaDriftTotal。
+// 差异不会导致请求失败，onDrift在发起请求的goroutine中同步调用。
+func WithStrictDecoding(onDrift func(SchemaDrift)) Option {
+	return func(c *Client) {
+		c.onDrift = onDrift
+	}
+}
+
+// decode 解析响应，开启严格解析模式时检查字段差异
+func (c *Client) decode(endpoint string, data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		if c.metrics != nil {
+			c.metrics.Add(metrics.DecodeErrorsTotal, 1, metrics.L("endpoint", routeOf(endpoint)))
+		}
+		return err
+	}
+
+	if c.onDrift != nil {
+		drift, err := CheckSchema(data, v)
+		if err == nil && !drift.Empty() {
+			drift.Endpoint = endpoint
+			drift.Route = routeOf(endpoint)
+			if c.metrics != nil {
+				c.metrics.Add(metrics.SchemaDriftTotal, 1, metrics.L("endpoint", drift.Route))
+			}
+			c.onDrift(drift)
+		}
+	}
+	return nil
+}
+
+// CheckSchema 比较JSON数据与v的类型，返回未知字段和缺失字段
+// 实现了json.Unmarshaler的类型（例如Optional、Int和amounts中的类型）视为叶子节点，
+// 带有omitempty标签的字段缺失时不会被报告。
+func CheckSchema(data []byte, v any) (SchemaDrift, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return SchemaDrift{}, fmt.Errorf("无效的目标类型")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return SchemaDrift{}, fmt.Errorf("解析JSON失败: %w", err)
+	}
+
+	w := &schemaWalker{unknown: make(map[string]bool), missing: make(map[string]bool)}
+	w.walk("", t, raw)
+
+	drift := SchemaDrift{Type: t.Name()}
+	for path := range w.unknown {
+		drift.Unknown = append(drift.Unknown, path)
+	}
+	for path := range w.missing {
+		drift.Missing = append(drift.Missing, path)
+	}
+	slices.Sort(drift.Unknown)
+	slices.Sort(drift.Missing)
+	return drift, nil
+}
+
+var unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+// schemaWalker 递归比较JSON值与Go类型
+type schemaWalker struct {
+	unknown map[string]bool
+	missing map[string]bool
+}
+
+// walk 比较路径path处的JSON值raw与类型t
+func (w *schemaWalker) walk(path string, t reflect.Type, raw any) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if raw == nil || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, value := range obj {
+			field, ok := fields[key]
+			if !ok {
+				w.unknown[joinPath(path, key)] = true
+				continue
+			}
+			w.walk(joinPath(path, key), field.typ, value)
+		}
+		for name, field := range fields {
+			if _, ok := obj[name]; !ok && !field.omitEmpty {
+				w.missing[joinPath(path, name)] = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := raw.([]any)
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			w.walk(path+"[]", t.Elem(), item)
+		}
+	case reflect.Map:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return
+		}
+		for _, value := range obj {
+			w.walk(path+"{}", t.Elem(), value)
+		}
+	}
+}
+
+// jsonField 结构体字段的JSON信息
+type jsonField struct {
+	typ       reflect.Type
+	omitEmpty bool
+}
+
+// jsonFields 返回结构体按JSON名称索引的字段，包含嵌入结构体的字段
+func jsonFields(t reflect.Type) map[string]jsonField {
+	fields := make(map[string]jsonField)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					if _, ok := fields[k]; !ok {
+						fields[k] = v
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = jsonField{typ: f.Type, omitEmpty: strings.Contains(opts, "omitempty")}
+	}
+	return fields
+}
+
+// responseTypes 各端点的响应类型
+var responseTypes = map[string]func() any{
+	"/user/profile":       func() any { return new(OdinUser) },
+	"/user/{id}":          func() any { return new(OdinUser) },
+	"/user/{id}/balances": func() any { return new(OdinUserBalance) },
+	"/tokens":             func() any { return new(OdinFunTokens) },
+	"/token/{id}":         func() any { return new(TokenDetail) },
+	"/token/{id}/owners":  func() any { return new(Holders) },
+	"/token/{id}/trades":  func() any { return new(TokenTraders) },
+	"/currency/btc":       func() any { return new(BTCInfo) },
+}
+
+// ResponseFor 返回端点响应类型的新实例（指针），可传给CheckSchema
+// endpoint为相对于BaseURL的路径，可以包含查询参数；未知端点返回false
+func ResponseFor(endpoint string) (any, bool) {
+	newResponse, ok := responseTypes[routeOf(endpoint)]
+	if !ok {
+		return nil, false
+	}
+	return newResponse(), true
+}
+
+// joinPath 拼接字段路径
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// routeOf 去掉端点中的查询参数，并将用户和代币ID替换为{id}
+func routeOf(endpoint string) string {
+	path, _, _ := strings.Cut(endpoint, "?")
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts); i++ {
+		if (parts[i-1] == "user" && parts[i] != "profile") || parts[i-1] == "token" {
+			parts[i] = "{id}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
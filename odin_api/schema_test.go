@@ -0,0 +1,140 @@
+package odin_api_test
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+type schemaInner struct {
+	Name string `json:"name"`
+}
+
+type schemaBase struct {
+	ID string `json:"id"`
+}
+
+type schemaOuter struct {
+	schemaBase
+	Items   []schemaInner          `json:"items"`
+	Lookup  map[string]schemaInner `json:"lookup"`
+	Price   odin_api.Optional[int] `json:"price"`
+	Note    string                 `json:"note,omitempty"`
+	Ignored string                 `json:"-"`
+	Nested  *schemaInner           `json:"nested"`
+}
+
+func TestCheckSchema(t *testing.T) {
+	data := []byte(`{
+		"id": "1",
+		"items": [{"name": "a", "extra": 1}, {"other": true}],
+		"lookup": {"k": {"name": "b", "more": []}},
+		"price": {"unexpected": "shape"},
+		"nested": null,
+		"new_field": 1
+	}`)
+
+	drift, err := odin_api.CheckSchema(data, &schemaOuter{})
+	if err != nil {
+		t.Fatalf("CheckSchema: %v", err)
+	}
+	if drift.Type != "schemaOuter" {
+		t.Errorf("Type = %s, want schemaOuter", drift.Type)
+	}
+	wantUnknown := []string{"items[].extra", "items[].other", "lookup{}.more", "new_field"}
+	if !slices.Equal(drift.Unknown, wantUnknown) {
+		t.Errorf("Unknown = %v, want %v", drift.Unknown, wantUnknown)
+	}
+	// 带omitempty的note不报告缺失，Optional视为叶子节点
+	wantMissing := []string{"items[].name"}
+	if !slices.Equal(drift.Missing, wantMissing) {
+		t.Errorf("Missing = %v, want %v", drift.Missing, wantMissing)
+	}
+	if drift.Empty() {
+		t.Error("Empty() = true, want false")
+	}
+}
+
+func TestCheckSchemaNoDrift(t *testing.T) {
+	drift, err := odin_api.CheckSchema([]byte(`{"id":"1","items":[],"lookup":{},"price":null,"nested":{"name":"x"}}`), &schemaOuter{})
+	if err != nil {
+		t.Fatalf("CheckSchema: %v", err)
+	}
+	if !drift.Empty() {
+		t.Errorf("drift = %s, want empty", drift)
+	}
+}
+
+func TestCheckSchemaInvalidJSON(t *testing.T) {
+	if _, err := odin_api.CheckSchema([]byte(`{`), &schemaOuter{}); err == nil {
+		t.Error("CheckSchema(invalid JSON) succeeded, want error")
+	}
+}
+
+func TestResponseFor(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     any
+	}{
+		{"/token/2jjj/trades?page=1&limit=10", &odin_api.TokenTraders{}},
+		{"/token/2jjj", &odin_api.TokenDetail{}},
+		{"/user/profile?user=abc", &odin_api.OdinUser{}},
+		{"/user/abc/balances", &odin_api.OdinUserBalance{}},
+		{"/tokens?sort=price%3Adesc", &odin_api.OdinFunTokens{}},
+	}
+	for _, tt := range tests {
+		got, ok := odin_api.ResponseFor(tt.endpoint)
+		if !ok {
+			t.Errorf("ResponseFor(%s) not found", tt.endpoint)
+			continue
+		}
+		if gotType, wantType := typeName(got), typeName(tt.want); gotType != wantType {
+			t.Errorf("ResponseFor(%s) = %s, want %s", tt.endpoint, gotType, wantType)
+		}
+	}
+	if _, ok := odin_api.ResponseFor("/unknown"); ok {
+		t.Error("ResponseFor(/unknown) found, want false")
+	}
+}
+
+func TestWithStrictDecoding(t *testing.T) {
+	var drifts []odin_api.SchemaDrift
+	client := statusServer(t, http.StatusOK, nil, `{"id":1,"symbol":"BTC","datetime":"2024-01-01T00:00:00Z","amount":60000,"source":"new"}`)
+	client = odin_api.NewClient(
+		odin_api.WithBaseURL(client.BaseURL()),
+		odin_api.WithStrictDecoding(func(d odin_api.SchemaDrift) { drifts = append(drifts, d) }),
+	)
+
+	if _, err := client.GetBTCPrice(); err != nil {
+		t.Fatalf("GetBTCPrice: %v", err)
+	}
+	if len(drifts) != 1 {
+		t.Fatalf("drifts = %d, want 1", len(drifts))
+	}
+	if d := drifts[0]; d.Route != "/currency/btc" || !slices.Equal(d.Unknown, []string{"source"}) {
+		t.Errorf("drift = %+v, want unknown source on /currency/btc", d)
+	}
+}
+
+func TestWithStrictDecodingMatchesFakeServer(t *testing.T) {
+	srv := newServer(t)
+	var drifts []odin_api.SchemaDrift
+	client := srv.NewClient(odin_api.WithStrictDecoding(func(d odin_api.SchemaDrift) { drifts = append(drifts, d) }))
+
+	if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+		t.Fatalf("GetOdinFunToken: %v", err)
+	}
+	if _, err := client.GetHolders("2jjj"); err != nil {
+		t.Fatalf("GetHolders: %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("drifts = %v, want none", drifts)
+	}
+}
+
+func typeName(v any) string {
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,202 @@
+package odin_api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SessionConfig Session的配置
+type SessionConfig struct {
+	// RefreshBefore 令牌到期前多久主动刷新，默认1分钟
+	RefreshBefore time.Duration
+	// Auth 认证时使用的推荐码和时间来源
+	Auth AuthOptions
+	// OnRefresh 每次成功获取新令牌后调用，调用时Session未持有锁，回调中可以调用Session的方法或发送请求
+	OnRefresh func(token string, expiry time.Time)
+}
+
+// Session 持有一个Identity并自动管理Bearer令牌
+// 令牌即将到期时主动刷新，请求返回401时被动刷新并重发一次原请求，可并发使用
+type Session struct {
+	client   *Client
+	identity Identity
+	cfg      SessionConfig
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewSession 创建绑定到客户端的Session，之后该客户端的所有请求都使用Session的令牌
+// 应在客户端开始发送请求之前调用
+func (c *Client) NewSession(identity Identity, cfg SessionConfig) *Session {
+	if cfg.RefreshBefore <= 0 {
+		cfg.RefreshBefore = time.Minute
+	}
+
+	// 认证请求使用不带Session的客户端副本，避免递归获取令牌
+	authClient := *c
+	authClient.session = nil
+	authClient.Token = ""
+	authClient.pipe = authClient.pipeline()
+
+	s := &Session{
+		client:   &authClient,
+		identity: identity,
+		cfg:      cfg,
+	}
+	c.session = s
+	c.pipe = c.pipeline()
+	return s
+}
+
+// Token 返回有效的令牌，令牌不存在或即将到期时先重新认证
+func (s *Session) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	if s.token != "" && !s.expiring(time.Now()) {
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+	return s.refreshAndUnlock(ctx)
+}
+
+// current 返回Session已持有的令牌，不检查是否到期，也不会触发认证
+func (s *Session) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.token
+}
+
+// Refresh 强制重新认证并返回新令牌
+func (s *Session) Refresh(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	return s.refreshAndUnlock(ctx)
+}
+
+// Expiry 返回当前令牌的到期时间，令牌未包含到期时间时返回零值
+func (s *Session) Expiry() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.expiry
+}
+
+// Invalidate 丢弃当前令牌，下一次请求时会重新认证
+func (s *Session) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = ""
+	s.expiry = time.Time{}
+}
+
+// refreshStale 在令牌被服务器拒绝后刷新令牌
+// 若其他goroutine已经完成刷新，则直接返回新令牌而不重复认证
+func (s *Session) refreshStale(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	if s.token != "" && s.token != stale {
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+	return s.refreshAndUnlock(ctx)
+}
+
+// SessionMiddleware 为每个请求设置会话令牌，服务器返回401时刷新令牌并重发一次
+func SessionMiddleware(s *Session) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			token, err := s.Token(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("获取授权令牌失败: %w", err)
+			}
+
+			authorized := withBearer(req, token)
+			resp, err := next.Do(authorized)
+			if !errors.Is(err, ErrUnauthorized) {
+				return resp, err
+			}
+
+			token, refreshErr := s.refreshStale(ctx, bearerToken(authorized))
+			if refreshErr != nil {
+				return nil, fmt.Errorf("%w (重新认证失败: %v)", err, refreshErr)
+			}
+			retry, rewindErr := rewind(req)
+			if rewindErr != nil {
+				return nil, rewindErr
+			}
+			return next.Do(withBearer(retry, token))
+		})
+	}
+}
+
+// expiring 判断令牌是否已进入主动刷新窗口，调用方需持有锁
+func (s *Session) expiring(now time.Time) bool {
+	return !s.expiry.IsZero() && now.Add(s.cfg.RefreshBefore).After(s.expiry)
+}
+
+// refreshAndUnlock 重新认证，调用方需持有锁，返回前释放锁
+// 认证期间持有锁以避免并发请求重复认证；OnRefresh在释放锁之后调用，避免回调访问Session时死锁
+func (s *Session) refreshAndUnlock(ctx context.Context) (string, error) {
+	token, err := s.client.Authenticate(ctx, s.identity, s.cfg.Auth)
+	if err != nil {
+		s.mu.Unlock()
+		return "", err
+	}
+
+	expiry, err := TokenExpiry(token)
+	if err != nil {
+		// 无法解析到期时间时仅依赖401被动刷新
+		expiry = time.Time{}
+	}
+
+	s.token = token
+	s.expiry = expiry
+	s.mu.Unlock()
+
+	if s.cfg.OnRefresh != nil {
+		s.cfg.OnRefresh(token, expiry)
+	}
+	return token, nil
+}
+
+// TokenExpiry 从JWT令牌的exp声明中解析到期时间
+// 该函数不校验签名，仅用于判断何时需要刷新
+func TokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("令牌不是有效的JWT格式")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("解码JWT载荷失败: %w", err)
+	}
+
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("解析JWT载荷失败: %w", err)
+	}
+	if claims.Exp == nil {
+		return time.Time{}, errors.New("JWT未包含exp声明")
+	}
+
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("解析exp声明失败: %w", err)
+	}
+	sec := int64(exp)
+	return time.Unix(sec, int64((exp-float64(sec))*float64(time.Second))), nil
+}
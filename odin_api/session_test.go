@@ -0,0 +1,139 @@
+package odin_api_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+func TestSessionRefreshesOnUnauthorized(t *testing.T) {
+	srv := newServer(t)
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal := identity.PrincipalText()
+
+	var (
+		mu      sync.Mutex
+		tokens  []string
+		expires []time.Time
+	)
+	client := srv.NewClient()
+	session := client.NewSession(identity, odin_api.SessionConfig{
+		OnRefresh: func(token string, expiry time.Time) {
+			mu.Lock()
+			tokens = append(tokens, token)
+			expires = append(expires, expiry)
+			mu.Unlock()
+		},
+	})
+
+	if _, err := client.PostComment("gm", principal, "2jjj"); err != nil {
+		t.Fatalf("first PostComment: %v", err)
+	}
+	srv.ExpireTokens()
+	if _, err := client.PostComment("gn", principal, "2jjj"); err != nil {
+		t.Fatalf("PostComment after tokens expired: %v", err)
+	}
+
+	if len(tokens) != 2 || tokens[0] == tokens[1] {
+		t.Fatalf("refreshed tokens = %d, want 2 distinct tokens", len(tokens))
+	}
+	if !session.Expiry().Equal(expires[1]) || session.Expiry().Before(time.Now()) {
+		t.Errorf("Expiry = %v, want %v in the future", session.Expiry(), expires[1])
+	}
+
+	comments := srv.Comments()
+	if len(comments) != 2 || comments[1].Message != "gn" || comments[1].User != principal {
+		t.Errorf("comments = %+v, want gm and gn by %s", comments, principal)
+	}
+}
+
+func TestSessionRefreshesConcurrentUnauthorizedOnce(t *testing.T) {
+	srv := newServer(t)
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal := identity.PrincipalText()
+
+	var (
+		mu        sync.Mutex
+		refreshes int
+	)
+	client := srv.NewClient()
+	client.NewSession(identity, odin_api.SessionConfig{
+		OnRefresh: func(string, time.Time) {
+			mu.Lock()
+			refreshes++
+			mu.Unlock()
+		},
+	})
+	if _, err := client.PostComment("gm", principal, "2jjj"); err != nil {
+		t.Fatalf("first PostComment: %v", err)
+	}
+	srv.ExpireTokens()
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.PostComment("gn", principal, "2jjj"); err != nil {
+				t.Errorf("PostComment: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if refreshes != 2 {
+		t.Errorf("refreshes = %d, want 2", refreshes)
+	}
+}
+
+func TestSessionOnRefreshCanUseSession(t *testing.T) {
+	srv := newServer(t)
+	identity, err := odin_api.NewRandomEd25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		session *odin_api.Session
+		seen    time.Time
+	)
+	client := srv.NewClient()
+	session = client.NewSession(identity, odin_api.SessionConfig{
+		// 回调中访问Session和发送请求不能死锁
+		OnRefresh: func(token string, expiry time.Time) {
+			seen = session.Expiry()
+			if got, err := session.Token(context.Background()); err != nil || got != token {
+				t.Errorf("Token in OnRefresh = %q, %v, want %q", got, err, token)
+			}
+			if _, err := client.GetOdinFunToken("2jjj"); err != nil {
+				t.Errorf("GetOdinFunToken in OnRefresh: %v", err)
+			}
+		},
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := session.Refresh(context.Background())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Refresh: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Refresh deadlocked in OnRefresh")
+	}
+	if !seen.Equal(session.Expiry()) {
+		t.Errorf("Expiry in OnRefresh = %v, want %v", seen, session.Expiry())
+	}
+}
@@ -0,0 +1,237 @@
+package odin_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TradeStreamConfig TradeStream的配置
+type TradeStreamConfig struct {
+	Interval  time.Duration // 轮询间隔，默认5秒
+	PageLimit int           // 每页请求的交易数量，默认DefaultPageLimit
+	Buffer    int           // 交易通道的缓冲区大小
+	// StartTime 没有游标的代币从该时间开始获取交易，零值表示从TradeStream创建时开始
+	StartTime time.Time
+	// OnError 某个代币轮询失败时调用，轮询会在下一个周期继续
+	OnError func(tokenID string, err error)
+}
+
+// TradeCursor 单个代币的轮询游标，可序列化后持久化保存
+type TradeCursor struct {
+	TokenID string   `json:"token_id"`
+	Time    int64    `json:"time"`     // 已处理交易的最新时间戳（毫秒），作为下次请求的time_min
+	SeenIDs []string `json:"seen_ids"` // 时间戳等于Time的已处理交易ID，用于去重
+}
+
+// TradeStream 轮询一个或多个代币的交易历史，按时间顺序输出新交易
+type TradeStream struct {
+	client *Client
+	cfg    TradeStreamConfig
+	trades chan Trade
+
+	mu      sync.Mutex
+	cursors map[string]*TradeCursor
+}
+
+// NewTradeStream 创建一个轮询tokenIDs交易的TradeStream，调用Run开始轮询
+func (c *Client) NewTradeStream(cfg TradeStreamConfig, tokenIDs ...string) *TradeStream {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.PageLimit <= 0 {
+		cfg.PageLimit = DefaultPageLimit
+	}
+	if cfg.StartTime.IsZero() {
+		cfg.StartTime = time.Now()
+	}
+
+	s := &TradeStream{
+		client:  c,
+		cfg:     cfg,
+		trades:  make(chan Trade, cfg.Buffer),
+		cursors: make(map[string]*TradeCursor),
+	}
+	for _, id := range tokenIDs {
+		s.Add(id)
+	}
+	return s
+}
+
+// Trades 返回输出新交易的通道，Run返回后通道会被关闭
+func (s *TradeStream) Trades() <-chan Trade {
+	return s.trades
+}
+
+// Add 添加需要轮询的代币，已存在时不做任何改变
+func (s *TradeStream) Add(tokenID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.cursors[tokenID]; !ok {
+		s.cursors[tokenID] = &TradeCursor{
+			TokenID: tokenID,
+			Time:    s.cfg.StartTime.UnixMilli(),
+		}
+	}
+}
+
+// Remove 停止轮询指定代币
+func (s *TradeStream) Remove(tokenID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.cursors, tokenID)
+}
+
+// Cursors 返回所有代币当前游标的副本
+func (s *TradeStream) Cursors() []TradeCursor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursors := make([]TradeCursor, 0, len(s.cursors))
+	for _, cursor := range s.cursors {
+		c := *cursor
+		c.SeenIDs = slices.Clone(cursor.SeenIDs)
+		cursors = append(cursors, c)
+	}
+	slices.SortFunc(cursors, func(a, b TradeCursor) int {
+		return strings.Compare(a.TokenID, b.TokenID)
+	})
+	return cursors
+}
+
+// Restore 恢复之前保存的游标，恢复的代币会被加入轮询
+func (s *TradeStream) Restore(cursors []TradeCursor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, cursor := range cursors {
+		c := cursor
+		c.SeenIDs = slices.Clone(cursor.SeenIDs)
+		s.cursors[c.TokenID] = &c
+	}
+}
+
+// SaveCursors 将当前游标以JSON格式写入w
+func (s *TradeStream) SaveCursors(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(s.Cursors()); err != nil {
+		return fmt.Errorf("保存交易游标失败: %w", err)
+	}
+	return nil
+}
+
+// LoadCursors 从r读取SaveCursors保存的游标并恢复
+func (s *TradeStream) LoadCursors(r io.Reader) error {
+	var cursors []TradeCursor
+	if err := json.NewDecoder(r).Decode(&cursors); err != nil {
+		return fmt.Errorf("读取交易游标失败: %w", err)
+	}
+	s.Restore(cursors)
+	return nil
+}
+
+// Run 开始轮询，直到ctx被取消时返回ctx.Err()并关闭交易通道
+func (s *TradeStream) Run(ctx context.Context) error {
+	defer close(s.trades)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.poll(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll 轮询所有代币一次，并按时间顺序输出新交易
+func (s *TradeStream) poll(ctx context.Context) error {
+	var fresh []Trade
+	for _, cursor := range s.Cursors() {
+		trades, err := s.fetch(ctx, cursor)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if s.cfg.OnError != nil {
+				s.cfg.OnError(cursor.TokenID, err)
+			}
+			continue
+		}
+		fresh = append(fresh, trades...)
+	}
+
+	slices.SortStableFunc(fresh, func(a, b Trade) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	for _, trade := range fresh {
+		select {
+		case s.trades <- trade:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		s.advance(trade)
+	}
+	return nil
+}
+
+// fetch 获取代币在游标之后的新交易，已处理过的交易会被过滤
+func (s *TradeStream) fetch(ctx context.Context, cursor TradeCursor) ([]Trade, error) {
+	seen := make(map[string]struct{}, len(cursor.SeenIDs))
+	for _, id := range cursor.SeenIDs {
+		seen[id] = struct{}{}
+	}
+
+	target := TokenTarget{Id: cursor.TokenID, LastActionTimestamp: cursor.Time}
+	var trades []Trade
+	for trade, err := range s.client.AllTrades(ctx, target, s.cfg.PageLimit) {
+		if err != nil {
+			return nil, err
+		}
+		if trade.Time.UnixMilli() < cursor.Time {
+			continue
+		}
+		if _, ok := seen[trade.ID]; ok {
+			continue
+		}
+		seen[trade.ID] = struct{}{}
+		if trade.Token == "" {
+			trade.Token = cursor.TokenID
+		}
+		trades = append(trades, trade)
+	}
+	return trades, nil
+}
+
+// advance 在交易输出后推进对应代币的游标
+func (s *TradeStream) advance(trade Trade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursor, ok := s.cursors[trade.Token]
+	if !ok {
+		return
+	}
+
+	ts := trade.Time.UnixMilli()
+	switch {
+	case ts > cursor.Time:
+		cursor.Time = ts
+		cursor.SeenIDs = []string{trade.ID}
+	case ts == cursor.Time:
+		cursor.SeenIDs = append(cursor.SeenIDs, trade.ID)
+	}
+}
@@ -0,0 +1,91 @@
+package odin_api_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+// receive 从交易通道读取n笔交易并返回其ID，超时时测试失败
+func receive(t *testing.T, trades <-chan odin_api.Trade, n int) []string {
+	t.Helper()
+
+	var ids []string
+	timeout := time.After(2 * time.Second)
+	for len(ids) < n {
+		select {
+		case trade, ok := <-trades:
+			if !ok {
+				t.Fatalf("trade channel closed after %v", ids)
+			}
+			ids = append(ids, trade.ID)
+		case <-timeout:
+			t.Fatalf("received %v, want %d trades", ids, n)
+		}
+	}
+	return ids
+}
+
+func TestTradeStreamDeduplicatesEqualTimestamps(t *testing.T) {
+	srv := newServer(t)
+	base := time.Now().Truncate(time.Millisecond)
+	srv.AddTrades("2jjj",
+		odin_api.Trade{ID: "a", Time: base.Add(time.Millisecond)},
+		odin_api.Trade{ID: "b", Time: base.Add(2 * time.Millisecond)},
+		odin_api.Trade{ID: "c", Time: base.Add(2 * time.Millisecond)},
+	)
+
+	client := srv.NewClient()
+	stream := client.NewTradeStream(odin_api.TradeStreamConfig{Interval: 10 * time.Millisecond, StartTime: base}, "2jjj")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- stream.Run(ctx) }()
+
+	if got := receive(t, stream.Trades(), 3); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("first batch = %v, want [a b c]", got)
+	}
+
+	// 与已处理交易时间戳相同的新交易仍然输出，已处理的交易不会重复
+	srv.AddTrades("2jjj",
+		odin_api.Trade{ID: "d", Time: base.Add(2 * time.Millisecond)},
+		odin_api.Trade{ID: "e", Time: base.Add(3 * time.Millisecond)},
+	)
+	if got := receive(t, stream.Trades(), 2); !slices.Equal(got, []string{"d", "e"}) {
+		t.Errorf("second batch = %v, want [d e]", got)
+	}
+
+	// 再轮询几次确认没有重复
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+	for trade := range stream.Trades() {
+		t.Errorf("unexpected duplicate trade %s", trade.ID)
+	}
+
+	cursors := stream.Cursors()
+	if len(cursors) != 1 || cursors[0].Time != base.Add(3*time.Millisecond).UnixMilli() || !slices.Equal(cursors[0].SeenIDs, []string{"e"}) {
+		t.Errorf("cursors = %+v, want time of e with SeenIDs [e]", cursors)
+	}
+}
+
+func TestTradeStreamSkipsTradesBeforeStartTime(t *testing.T) {
+	srv := newServer(t)
+	base := time.Now().Truncate(time.Millisecond)
+	srv.AddTrades("2jjj",
+		odin_api.Trade{ID: "old", Time: base.Add(-time.Minute)},
+		odin_api.Trade{ID: "new", Time: base.Add(time.Millisecond)},
+	)
+
+	stream := srv.NewClient().NewTradeStream(odin_api.TradeStreamConfig{Interval: time.Hour, StartTime: base}, "2jjj")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	got := receive(t, stream.Trades(), 1)
+	if got[0] != "new" {
+		t.Errorf("trade = %s, want new", got[0])
+	}
+}
@@ -0,0 +1,68 @@
+package odin_api
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/MrHat365/odin-go/tracing"
+)
+
+// WithTracer 为Get、Post和PostMultipart请求创建span
+// span名称为 "odin_api 方法 路由"，属性包括端点路由、HTTP方法以及端点中的代币ID和用户Principal，
+// 请求失败时记录HTTP状态码。缓存命中和重试都包含在同一个span中。未设置时不创建span。
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// startSpan 为一次Get/Post请求开始span，返回携带span的ctx和结束span的函数
+// 未设置Tracer时原样返回ctx
+func (c *Client) startSpan(ctx context.Context, method, endpoint string) (context.Context, func(error)) {
+	if c.tracer == nil {
+		return ctx, func(error) {}
+	}
+
+	route := routeOf(endpoint)
+	attrs := []tracing.Attribute{
+		tracing.String(tracing.AttrEndpoint, route),
+		tracing.String(tracing.AttrHTTPMethod, method),
+	}
+	tokenID, principal := endpointParams(endpoint)
+	if tokenID != "" {
+		attrs = append(attrs, tracing.String(tracing.AttrTokenID, tokenID))
+	}
+	if principal != "" {
+		attrs = append(attrs, tracing.String(tracing.AttrPrincipal, principal))
+	}
+
+	ctx, span := c.tracer.Start(ctx, "odin_api "+method+" "+route, attrs...)
+	return ctx, func(err error) {
+		if status := statusOf(nil, err); status != 0 {
+			span.SetAttributes(tracing.Int(tracing.AttrHTTPStatus, status))
+		}
+		span.End(err)
+	}
+}
+
+// endpointParams 返回端点中的代币ID和用户Principal
+// 代币ID取自 /token/{id}，用户取自 /user/{id} 或查询参数user
+func endpointParams(endpoint string) (tokenID, principal string) {
+	path, query, _ := strings.Cut(endpoint, "?")
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts); i++ {
+		switch {
+		case parts[i-1] == "token" && tokenID == "":
+			tokenID = parts[i]
+		case parts[i-1] == "user" && parts[i] != "profile" && principal == "":
+			principal = parts[i]
+		}
+	}
+	if principal == "" {
+		if values, err := url.ParseQuery(query); err == nil {
+			principal = values.Get("user")
+		}
+	}
+	return tokenID, principal
+}
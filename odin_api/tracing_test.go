@@ -0,0 +1,95 @@
+package odin_api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+	"github.com/MrHat365/odin-go/odin_api/odintest"
+	"github.com/MrHat365/odin-go/tracing"
+)
+
+func TestTracerSpans(t *testing.T) {
+	srv := newServer(t)
+	srv.AddFault(odintest.Fault{Status: http.StatusServiceUnavailable, Times: 1})
+	recorder := tracing.NewRecorder()
+	client := srv.NewClient(
+		odin_api.WithTracer(recorder),
+		odin_api.WithRetryPolicy(odin_api.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+
+	ctx, parent := recorder.Start(context.Background(), "strategy")
+	if _, err := client.GetOdinFunTokenCtx(ctx, "2jjj"); err != nil {
+		t.Fatalf("GetOdinFunTokenCtx: %v", err)
+	}
+	parent.End(nil)
+
+	// 重试包含在同一个span中
+	spans := recorder.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want request span and parent", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "odin_api GET /token/{id}" || span.ParentID != spans[1].ID || span.Err != nil {
+		t.Errorf("span = %+v, want successful child span odin_api GET /token/{id}", span)
+	}
+	for key, want := range map[string]any{
+		tracing.AttrEndpoint:   "/token/{id}",
+		tracing.AttrHTTPMethod: "GET",
+		tracing.AttrTokenID:    "2jjj",
+	} {
+		if got, _ := span.Attr(key); got != want {
+			t.Errorf("Attr(%s) = %v, want %v", key, got, want)
+		}
+	}
+	if _, ok := span.Attr(tracing.AttrHTTPStatus); ok {
+		t.Error("successful span has a status attribute")
+	}
+}
+
+func TestTracerFailedSpan(t *testing.T) {
+	srv := newServer(t)
+	recorder := tracing.NewRecorder()
+	client := srv.NewClient(odin_api.WithTracer(recorder))
+
+	_, err := client.GetOdinFunUser("missing-user")
+	if !errors.Is(err, odin_api.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+
+	spans := recorder.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "odin_api GET /user/{id}" || !errors.Is(span.Err, odin_api.ErrNotFound) {
+		t.Errorf("span = %+v, want failed odin_api GET /user/{id}", span)
+	}
+	if got, _ := span.Attr(tracing.AttrHTTPStatus); got != http.StatusNotFound {
+		t.Errorf("status = %v, want 404", got)
+	}
+	if got, _ := span.Attr(tracing.AttrPrincipal); got != "missing-user" {
+		t.Errorf("principal = %v, want missing-user", got)
+	}
+}
+
+func TestTracerPrincipalFromQuery(t *testing.T) {
+	srv := newServer(t)
+	recorder := tracing.NewRecorder()
+	client := srv.NewClient(odin_api.WithTracer(recorder))
+
+	client.PostComment("gm", "user-1", "2jjj")
+	spans := recorder.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	if got, _ := spans[0].Attr(tracing.AttrPrincipal); got != "user-1" {
+		t.Errorf("principal = %v, want user-1", got)
+	}
+	if got, _ := spans[0].Attr(tracing.AttrTokenID); got != "2jjj" {
+		t.Errorf("token = %v, want 2jjj", got)
+	}
+}
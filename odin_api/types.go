@@ -0,0 +1,166 @@
+package odin_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Optional 表示一个可能为null或缺失的值
+// 字段为null、缺失或为空字符串时HasValue为false
+type Optional[T any] struct {
+	HasValue bool
+	Value    T
+}
+
+// Some 创建一个有值的Optional
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{HasValue: true, Value: v}
+}
+
+// Get 返回值以及是否有值
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.HasValue
+}
+
+// OrElse 有值时返回该值，否则返回def
+func (o Optional[T]) OrElse(def T) T {
+	if !o.HasValue {
+		return def
+	}
+	return o.Value
+}
+
+// MarshalJSON 无值时编码为null
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.HasValue {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON 解析null、空字符串或T的JSON表示
+// T为string时数字和布尔值会按原样保存为字符串
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	var zero T
+	o.HasValue, o.Value = false, zero
+
+	data = bytes.TrimSpace(data)
+	if isNull(data) || bytes.Equal(data, []byte(`""`)) {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &o.Value); err != nil {
+		s, ok := any(&o.Value).(*string)
+		if !ok || len(data) == 0 || data[0] == '{' || data[0] == '[' {
+			return err
+		}
+		*s = string(data)
+	}
+	o.HasValue = true
+	return nil
+}
+
+// Int 可以从JSON数字或数字字符串解析的整数，null和空字符串解析为0
+type Int int64
+
+// UnmarshalJSON 解析数字、数字字符串或null
+// 数字字符串必须是十进制整数；JSON数字还可以是值为整数的小数或科学计数法，例如 10.0 或 1e3
+func (i *Int) UnmarshalJSON(data []byte) error {
+	s, ok := unquoteNumber(data)
+	if !ok {
+		*i = 0
+		return nil
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		*i = Int(v)
+		return nil
+	}
+	if errors.Is(err, strconv.ErrRange) || bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		return fmt.Errorf("无法将 %s 解析为整数", data)
+	}
+
+	// float64(math.MaxInt64)等于2^63，因此上界必须使用>=比较
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f >= 0x1p63 || f < -0x1p63 {
+		return fmt.Errorf("无法将 %s 解析为整数", data)
+	}
+	*i = Int(f)
+	return nil
+}
+
+// Float 可以从JSON数字或数字字符串解析的浮点数，null和空字符串解析为0
+type Float float64
+
+// UnmarshalJSON 解析数字、数字字符串或null
+func (f *Float) UnmarshalJSON(data []byte) error {
+	s, ok := unquoteNumber(data)
+	if !ok {
+		*f = 0
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("无法将 %s 解析为数字", data)
+	}
+	*f = Float(v)
+	return nil
+}
+
+// Timestamp 可以从RFC 3339字符串或Unix时间戳（秒或毫秒）解析的时间
+type Timestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON 解析RFC 3339字符串、Unix时间戳或null
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	s, ok := unquoteNumber(data)
+	if !ok {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		// 超过1e11的值按毫秒处理，1e11秒约为公元5138年
+		if v > 1e11 || v < -1e11 {
+			t.Time = time.UnixMilli(v).UTC()
+		} else {
+			t.Time = time.Unix(v, 0).UTC()
+		}
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return fmt.Errorf("无法将 %s 解析为时间: %w", data, err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// unquoteNumber 去掉JSON值两侧的引号，null或空字符串时ok为false
+func unquoteNumber(data []byte) (s string, ok bool) {
+	data = bytes.TrimSpace(data)
+	if isNull(data) {
+		return "", false
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = bytes.TrimSpace(data[1 : len(data)-1])
+	}
+	if len(data) == 0 {
+		return "", false
+	}
+	return string(data), true
+}
+
+// isNull 判断JSON值是否为null
+func isNull(data []byte) bool {
+	return len(data) == 0 || bytes.Equal(data, []byte("null"))
+}
@@ -0,0 +1,143 @@
+package odin_api_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/MrHat365/odin-go/odin_api"
+)
+
+func TestIntUnmarshal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    odin_api.Int
+		wantErr bool
+	}{
+		{`42`, 42, false},
+		{`"42"`, 42, false},
+		{`-7`, -7, false},
+		{`10.0`, 10, false},
+		{`1e3`, 1000, false},
+		{`null`, 0, false},
+		{`""`, 0, false},
+		{`"9223372036854775807"`, 1<<63 - 1, false},
+		{`9223372036854775807`, 1<<63 - 1, false},
+		{`9223372036854775808`, 0, true},
+		{`9.223372036854775807e18`, 0, true},
+		{`"9223372036854775808"`, 0, true},
+		{`"1e3"`, 0, true},
+		{`1.5`, 0, true},
+		{`"abc"`, 0, true},
+	}
+	for _, tt := range tests {
+		var got odin_api.Int
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFloatUnmarshal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    odin_api.Float
+		wantErr bool
+	}{
+		{`1.5`, 1.5, false},
+		{`"60123.45"`, 60123.45, false},
+		{`null`, 0, false},
+		{`""`, 0, false},
+		{`"n/a"`, 0, true},
+	}
+	for _, tt := range tests {
+		var got odin_api.Float
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTimestampUnmarshal(t *testing.T) {
+	want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{`"2025-01-02T03:04:05Z"`, want},
+		{`1735787045`, want},
+		{`"1735787045"`, want},
+		{`1735787045000`, want},
+		{`null`, time.Time{}},
+	}
+	for _, tt := range tests {
+		var got odin_api.Timestamp
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got.Time, tt.want)
+		}
+	}
+
+	var got odin_api.Timestamp
+	if err := json.Unmarshal([]byte(`"yesterday"`), &got); err == nil {
+		t.Error("Unmarshal(\"yesterday\") succeeded, want error")
+	}
+}
+
+func TestOptionalUnmarshal(t *testing.T) {
+	var v struct {
+		Missing odin_api.Optional[string] `json:"missing"`
+		Null    odin_api.Optional[string] `json:"null"`
+		Empty   odin_api.Optional[string] `json:"empty"`
+		Text    odin_api.Optional[string] `json:"text"`
+		Number  odin_api.Optional[string] `json:"number"`
+		Int     odin_api.Optional[int]    `json:"int"`
+	}
+	data := `{"null":null,"empty":"","text":"hi","number":12.5,"int":3}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if v.Missing.HasValue || v.Null.HasValue || v.Empty.HasValue {
+		t.Errorf("missing, null and empty = %+v, %+v, %+v, want no value", v.Missing, v.Null, v.Empty)
+	}
+	if got, ok := v.Text.Get(); !ok || got != "hi" {
+		t.Errorf("Text = %q, %v, want hi", got, ok)
+	}
+	if got := v.Number.OrElse(""); got != "12.5" {
+		t.Errorf("Number = %q, want 12.5", got)
+	}
+	if got := v.Int.OrElse(-1); got != 3 {
+		t.Errorf("Int = %d, want 3", got)
+	}
+	if got := v.Missing.OrElse("default"); got != "default" {
+		t.Errorf("Missing.OrElse = %q, want default", got)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"missing":null,"null":null,"empty":null,"text":"hi","number":"12.5","int":3}`
+	if string(out) != want {
+		t.Errorf("Marshal = %s, want %s", out, want)
+	}
+
+	var bad odin_api.Optional[int]
+	if err := json.Unmarshal([]byte(`"three"`), &bad); err == nil {
+		t.Error("Unmarshal(\"three\") into Optional[int] succeeded, want error")
+	}
+}
@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SpanData 已结束的span
+type SpanData struct {
+	ID         uint64
+	ParentID   uint64 // 父span的ID，没有父span时为0
+	Name       string
+	Attributes []Attribute
+	Start      time.Time
+	End        time.Time
+	Err        error
+}
+
+// Attr 返回名为key的属性值
+func (s SpanData) Attr(key string) (any, bool) {
+	for i := len(s.Attributes) - 1; i >= 0; i-- {
+		if s.Attributes[i].Key == key {
+			return s.Attributes[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Duration 返回span的耗时
+func (s SpanData) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Recorder 在内存中记录span的Tracer实现，适用于测试和调试
+// 父子关系通过context传递，只有同一个Recorder创建的span会被识别为父span
+type Recorder struct {
+	mu    sync.Mutex
+	next  uint64
+	spans []SpanData
+}
+
+// NewRecorder 创建空的Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// spanKey context中保存当前span的键
+type spanKey struct{}
+
+// Start 实现Tracer
+func (r *Recorder) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	r.mu.Lock()
+	r.next++
+	id := r.next
+	r.mu.Unlock()
+
+	s := &recordedSpan{
+		recorder: r,
+		data: SpanData{
+			ID:         id,
+			Name:       name,
+			Attributes: slices.Clone(attrs),
+			Start:      time.Now(),
+		},
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok && parent.recorder == r {
+		s.data.ParentID = parent.data.ID
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Spans 返回已结束的span，按结束的先后顺序排列
+func (r *Recorder) Spans() []SpanData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spans := make([]SpanData, len(r.spans))
+	for i, s := range r.spans {
+		s.Attributes = slices.Clone(s.Attributes)
+		spans[i] = s
+	}
+	return spans
+}
+
+// Reset 清空已记录的span
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = nil
+}
+
+// recordedSpan Recorder创建的进行中的span
+type recordedSpan struct {
+	recorder *Recorder
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SetAttributes 实现Span
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended {
+		s.data.Attributes = append(s.data.Attributes, attrs...)
+	}
+}
+
+// End 实现Span，重复调用时忽略
+func (s *recordedSpan) End(err error) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	s.data.Err = err
+	data := s.data
+	s.mu.Unlock()
+
+	s.recorder.mu.Lock()
+	s.recorder.spans = append(s.recorder.spans, data)
+	s.recorder.mu.Unlock()
+}
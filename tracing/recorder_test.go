@@ -0,0 +1,73 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MrHat365/odin-go/tracing"
+)
+
+func TestRecorderParentChild(t *testing.T) {
+	r := tracing.NewRecorder()
+	ctx, parent := r.Start(context.Background(), "parent", tracing.String("a", "1"))
+	_, child := r.Start(ctx, "child")
+	child.End(nil)
+	parent.SetAttributes(tracing.String("a", "2"), tracing.Int("n", 3))
+	parent.End(nil)
+
+	spans := r.Spans()
+	if len(spans) != 2 || spans[0].Name != "child" || spans[1].Name != "parent" {
+		t.Fatalf("spans = %+v, want child then parent", spans)
+	}
+	if spans[0].ParentID != spans[1].ID || spans[1].ParentID != 0 {
+		t.Errorf("child.ParentID = %d, parent.ID = %d, parent.ParentID = %d", spans[0].ParentID, spans[1].ID, spans[1].ParentID)
+	}
+	if v, _ := spans[1].Attr("a"); v != "2" {
+		t.Errorf("Attr(a) = %v, want the latest value 2", v)
+	}
+	if v, _ := spans[1].Attr("n"); v != 3 {
+		t.Errorf("Attr(n) = %v, want 3", v)
+	}
+	if _, ok := spans[1].Attr("missing"); ok {
+		t.Error("Attr(missing) found")
+	}
+	if spans[1].Duration() < 0 {
+		t.Errorf("Duration = %v, want non-negative", spans[1].Duration())
+	}
+}
+
+func TestRecorderEndOnce(t *testing.T) {
+	r := tracing.NewRecorder()
+	_, span := r.Start(context.Background(), "op")
+	failure := errors.New("boom")
+	span.End(failure)
+	span.End(nil)
+	span.SetAttributes(tracing.String("late", "x"))
+
+	spans := r.Spans()
+	if len(spans) != 1 || !errors.Is(spans[0].Err, failure) {
+		t.Fatalf("spans = %+v, want one failed span", spans)
+	}
+	if _, ok := spans[0].Attr("late"); ok {
+		t.Error("attribute set after End was recorded")
+	}
+
+	r.Reset()
+	if n := len(r.Spans()); n != 0 {
+		t.Errorf("spans after Reset = %d, want 0", n)
+	}
+}
+
+func TestRecorderIgnoresForeignParent(t *testing.T) {
+	other := tracing.NewRecorder()
+	ctx, foreign := other.Start(context.Background(), "foreign")
+	defer foreign.End(nil)
+
+	r := tracing.NewRecorder()
+	_, span := r.Start(ctx, "op")
+	span.End(nil)
+	if parent := r.Spans()[0].ParentID; parent != 0 {
+		t.Errorf("ParentID = %d, want 0 for a span from another Recorder", parent)
+	}
+}
@@ -0,0 +1,59 @@
+// Package tracing 定义odin_api和agent_sdk的链路追踪接口，并提供内存中的Recorder实现
+//
+// SDK只依赖Tracer接口，未设置时不会产生任何开销。接口的形式与OpenTelemetry一致：
+// Start返回携带新span的context，同一context下发起的请求和Canister调用会成为它的子span，
+// 因此只需在业务代码中开始一个父span，就能把行情查询、报价和交易调用串成一条链路。
+// 接入OpenTelemetry时实现一个把Attribute转换为attribute.KeyValue的适配器即可。
+package tracing
+
+import "context"
+
+// SDK设置的span属性名称
+const (
+	// AttrEndpoint odin_api的端点路由，ID被替换为{id}，例如 /token/{id}/trades
+	AttrEndpoint = "odin.endpoint"
+	// AttrHTTPMethod odin_api请求的HTTP方法
+	AttrHTTPMethod = "http.request.method"
+	// AttrHTTPStatus odin_api请求失败时的HTTP状态码
+	AttrHTTPStatus = "http.response.status_code"
+	// AttrTokenID 请求或调用涉及的代币ID
+	AttrTokenID = "odin.token_id"
+	// AttrPrincipal 请求涉及的用户Principal，或发起Canister调用的Principal
+	AttrPrincipal = "odin.principal"
+	// AttrCanisterID 被调用的Canister ID
+	AttrCanisterID = "icp.canister_id"
+	// AttrCanisterMethod 被调用的Canister方法名
+	AttrCanisterMethod = "icp.method"
+	// AttrCallKind Canister调用的类型，query或update
+	AttrCallKind = "icp.call_kind"
+)
+
+// Attribute span的属性
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String 创建字符串属性
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int 创建整数属性
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Tracer 创建span，实现必须可以并发调用
+type Tracer interface {
+	// Start 开始一个名为name的span，返回携带该span的ctx
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span 一次进行中的操作
+type Span interface {
+	// SetAttributes 设置span的属性，同名属性会被覆盖
+	SetAttributes(attrs ...Attribute)
+	// End 结束span，err不为nil时span被标记为失败
+	End(err error)
+}